@@ -0,0 +1,215 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/youmna-rabie/claude-pod/internal/types"
+)
+
+// ErrCircuitOpen is returned by Breaker when it rejects a forward without
+// calling the agent.
+var ErrCircuitOpen = errors.New("agent circuit breaker is open")
+
+// BreakerState is the state of a circuit breaker.
+type BreakerState string
+
+const (
+	BreakerClosed   BreakerState = "closed"
+	BreakerOpen     BreakerState = "open"
+	BreakerHalfOpen BreakerState = "half-open"
+)
+
+// BreakerPolicy controls when a Breaker opens and how it recovers.
+type BreakerPolicy struct {
+	FailureThreshold int           // consecutive failures that open the circuit
+	Cooldown         time.Duration // time spent open before allowing trial requests
+	HalfOpenRequests int           // concurrent trial requests allowed while half-open
+}
+
+// BreakerSnapshot is a point-in-time view of a Breaker, suitable for
+// exposing on admin endpoints.
+type BreakerSnapshot struct {
+	State               BreakerState `json:"state"`
+	ConsecutiveFailures int          `json:"consecutive_failures"`
+	FailureThreshold    int          `json:"failure_threshold"`
+	Cooldown            string       `json:"cooldown"`
+	OpenedAt            *time.Time   `json:"opened_at,omitempty"`
+	LastError           string       `json:"last_error,omitempty"`
+}
+
+// Breaker wraps a Client with a circuit breaker. After FailureThreshold
+// consecutive failures it opens and fails fast with ErrCircuitOpen until
+// Cooldown elapses, then lets a limited number of trial requests through.
+// A successful trial closes the circuit; a failed one re-opens it.
+//
+// Only failures that indicate an unhealthy agent count towards the threshold
+// (see IsRetryable); rejected requests such as 4xx responses do not.
+type Breaker struct {
+	next   Client
+	policy BreakerPolicy
+	logger *slog.Logger
+	now    func() time.Time
+
+	mu        sync.Mutex
+	state     BreakerState
+	gen       uint64 // incremented on every state change
+	failures  int
+	openedAt  time.Time
+	trials    int
+	lastError string
+}
+
+// NewBreaker wraps next with a circuit breaker using the given policy.
+func NewBreaker(next Client, policy BreakerPolicy, logger *slog.Logger) *Breaker {
+	if policy.FailureThreshold < 1 {
+		policy.FailureThreshold = 1
+	}
+	if policy.HalfOpenRequests < 1 {
+		policy.HalfOpenRequests = 1
+	}
+	return &Breaker{
+		next:   next,
+		policy: policy,
+		logger: logger,
+		now:    time.Now,
+		state:  BreakerClosed,
+	}
+}
+
+// Forward calls the wrapped client unless the circuit is open.
+func (b *Breaker) Forward(ctx context.Context, envelope types.EventEnvelope) (Response, error) {
+	gen, ok := b.allow()
+	if !ok {
+		return Response{}, ErrCircuitOpen
+	}
+
+	resp, err := b.next.Forward(ctx, envelope)
+	b.record(gen, err)
+	return resp, err
+}
+
+// State returns the current breaker state.
+func (b *Breaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.advance()
+	return b.state
+}
+
+// Snapshot returns the current breaker state and counters.
+func (b *Breaker) Snapshot() BreakerSnapshot {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.advance()
+
+	snap := BreakerSnapshot{
+		State:               b.state,
+		ConsecutiveFailures: b.failures,
+		FailureThreshold:    b.policy.FailureThreshold,
+		Cooldown:            b.policy.Cooldown.String(),
+		LastError:           b.lastError,
+	}
+	if b.state != BreakerClosed {
+		openedAt := b.openedAt
+		snap.OpenedAt = &openedAt
+	}
+	return snap
+}
+
+// allow reports whether a request may proceed, reserving a trial slot when
+// half-open. It returns the generation the request was admitted in, to be
+// passed to record.
+func (b *Breaker) allow() (uint64, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.advance()
+
+	switch b.state {
+	case BreakerOpen:
+		return b.gen, false
+	case BreakerHalfOpen:
+		if b.trials >= b.policy.HalfOpenRequests {
+			return b.gen, false
+		}
+		b.trials++
+		return b.gen, true
+	default:
+		return b.gen, true
+	}
+}
+
+// record updates the breaker with the outcome of a request admitted in
+// generation gen. Outcomes of requests admitted before the last state change
+// are ignored: a slow call from before an outage must neither close the
+// circuit nor take the place of a half-open trial.
+func (b *Breaker) record(gen uint64, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if gen != b.gen {
+		return
+	}
+	if b.state == BreakerHalfOpen {
+		b.trials--
+	}
+
+	// A cancelled call says nothing about the agent's health.
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+
+	if err == nil || !IsRetryable(err) {
+		if err == nil {
+			b.lastError = ""
+		}
+		b.failures = 0
+		if b.state != BreakerClosed {
+			b.transition(BreakerClosed)
+		}
+		return
+	}
+
+	b.failures++
+	b.lastError = err.Error()
+	if b.state == BreakerHalfOpen || b.failures >= b.policy.FailureThreshold {
+		b.openedAt = b.now()
+		b.transition(BreakerOpen)
+	}
+}
+
+// advance moves an open breaker to half-open once the cooldown has elapsed.
+// Callers must hold b.mu.
+func (b *Breaker) advance() {
+	if b.state == BreakerOpen && b.now().Sub(b.openedAt) >= b.policy.Cooldown {
+		b.trials = 0
+		b.transition(BreakerHalfOpen)
+	}
+}
+
+// transition changes state and logs it. Callers must hold b.mu.
+func (b *Breaker) transition(to BreakerState) {
+	if b.state == to {
+		return
+	}
+	from := b.state
+	b.state = to
+	b.gen++
+	if to == BreakerClosed {
+		b.trials = 0
+	}
+
+	level := slog.LevelInfo
+	if to == BreakerOpen {
+		level = slog.LevelWarn
+	}
+	b.logger.Log(context.Background(), level, "agent circuit breaker state changed",
+		"from", from,
+		"to", to,
+		"consecutive_failures", b.failures,
+		"last_error", b.lastError,
+	)
+}
@@ -0,0 +1,162 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// fakeClock is a manually advanced clock for breaker tests.
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) now() time.Time          { return c.t }
+func (c *fakeClock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func newTestBreaker(next Client, policy BreakerPolicy) (*Breaker, *fakeClock) {
+	clock := &fakeClock{t: time.Now()}
+	b := NewBreaker(next, policy, quietLogger())
+	b.now = clock.now
+	return b, clock
+}
+
+func TestBreaker_OpensAfterThreshold(t *testing.T) {
+	next := &flakyClient{failures: 100, err: &StatusError{StatusCode: http.StatusBadGateway}}
+	b, _ := newTestBreaker(next, BreakerPolicy{FailureThreshold: 3, Cooldown: time.Minute})
+
+	for i := 0; i < 3; i++ {
+		if b.State() != BreakerClosed {
+			t.Fatalf("state after %d failures = %s, want closed", i, b.State())
+		}
+		b.Forward(context.Background(), newTestEnvelope())
+	}
+	if b.State() != BreakerOpen {
+		t.Fatalf("state = %s, want open", b.State())
+	}
+
+	_, err := b.Forward(context.Background(), newTestEnvelope())
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("error = %v, want ErrCircuitOpen", err)
+	}
+	if next.calls != 3 {
+		t.Errorf("calls = %d, want 3 (open breaker must not call the agent)", next.calls)
+	}
+}
+
+func TestBreaker_HalfOpenSuccessCloses(t *testing.T) {
+	next := &flakyClient{failures: 2, err: &StatusError{StatusCode: http.StatusServiceUnavailable}}
+	b, clock := newTestBreaker(next, BreakerPolicy{FailureThreshold: 2, Cooldown: time.Minute})
+
+	b.Forward(context.Background(), newTestEnvelope())
+	b.Forward(context.Background(), newTestEnvelope())
+	if b.State() != BreakerOpen {
+		t.Fatalf("state = %s, want open", b.State())
+	}
+
+	clock.advance(time.Minute)
+	if b.State() != BreakerHalfOpen {
+		t.Fatalf("state after cooldown = %s, want half-open", b.State())
+	}
+
+	if _, err := b.Forward(context.Background(), newTestEnvelope()); err != nil {
+		t.Fatalf("trial request failed: %v", err)
+	}
+	if b.State() != BreakerClosed {
+		t.Fatalf("state after successful trial = %s, want closed", b.State())
+	}
+}
+
+func TestBreaker_HalfOpenFailureReopens(t *testing.T) {
+	next := &flakyClient{failures: 100, err: &StatusError{StatusCode: http.StatusServiceUnavailable}}
+	b, clock := newTestBreaker(next, BreakerPolicy{FailureThreshold: 1, Cooldown: time.Minute})
+
+	b.Forward(context.Background(), newTestEnvelope())
+	clock.advance(time.Minute)
+	if b.State() != BreakerHalfOpen {
+		t.Fatalf("state = %s, want half-open", b.State())
+	}
+
+	b.Forward(context.Background(), newTestEnvelope())
+	if b.State() != BreakerOpen {
+		t.Fatalf("state after failed trial = %s, want open", b.State())
+	}
+}
+
+func TestBreaker_StaleResultDuringHalfOpen(t *testing.T) {
+	next := &flakyClient{failures: 1, err: &StatusError{StatusCode: http.StatusServiceUnavailable}}
+	b, clock := newTestBreaker(next, BreakerPolicy{FailureThreshold: 1, Cooldown: time.Minute})
+
+	// A slow call is admitted while closed, then the agent goes down.
+	slow, _ := b.allow()
+	b.Forward(context.Background(), newTestEnvelope())
+	clock.advance(time.Minute)
+
+	trial, ok := b.allow()
+	if !ok {
+		t.Fatal("trial not admitted after cooldown")
+	}
+
+	// The slow call finishing neither closes the circuit nor frees the
+	// trial's slot.
+	b.record(slow, nil)
+	if b.State() != BreakerHalfOpen {
+		t.Fatalf("state after stale success = %s, want half-open", b.State())
+	}
+	if _, ok := b.allow(); ok {
+		t.Fatal("second trial admitted beyond HalfOpenRequests")
+	}
+	b.record(slow, &StatusError{StatusCode: http.StatusBadGateway})
+	if b.State() != BreakerHalfOpen {
+		t.Fatalf("state after stale failure = %s, want half-open", b.State())
+	}
+
+	b.record(trial, nil)
+	if b.State() != BreakerClosed {
+		t.Fatalf("state after successful trial = %s, want closed", b.State())
+	}
+}
+
+func TestBreaker_ClientErrorsDoNotTrip(t *testing.T) {
+	next := &flakyClient{failures: 100, err: &StatusError{StatusCode: http.StatusBadRequest}}
+	b, _ := newTestBreaker(next, BreakerPolicy{FailureThreshold: 2, Cooldown: time.Minute})
+
+	for i := 0; i < 5; i++ {
+		b.Forward(context.Background(), newTestEnvelope())
+	}
+	if b.State() != BreakerClosed {
+		t.Fatalf("state = %s, want closed", b.State())
+	}
+}
+
+func TestBreaker_Snapshot(t *testing.T) {
+	next := &flakyClient{failures: 100, err: &StatusError{StatusCode: http.StatusBadGateway}}
+	b, _ := newTestBreaker(next, BreakerPolicy{FailureThreshold: 1, Cooldown: 30 * time.Second})
+
+	snap := b.Snapshot()
+	if snap.State != BreakerClosed || snap.OpenedAt != nil {
+		t.Fatalf("initial snapshot = %+v, want closed without opened_at", snap)
+	}
+
+	b.Forward(context.Background(), newTestEnvelope())
+	snap = b.Snapshot()
+	if snap.State != BreakerOpen {
+		t.Errorf("state = %s, want open", snap.State)
+	}
+	if snap.OpenedAt == nil {
+		t.Error("opened_at should be set while open")
+	}
+	if snap.ConsecutiveFailures != 1 {
+		t.Errorf("consecutive_failures = %d, want 1", snap.ConsecutiveFailures)
+	}
+	if snap.LastError == "" {
+		t.Error("last_error should be set")
+	}
+	if snap.Cooldown != "30s" {
+		t.Errorf("cooldown = %q, want %q", snap.Cooldown, "30s")
+	}
+}
+
+func TestBreaker_ImplementsClientInterface(t *testing.T) {
+	var _ Client = (*Breaker)(nil)
+}
@@ -12,6 +12,10 @@ type Response struct {
 	Status  string          `json:"status"`
 	EventID string          `json:"event_id"`
 	Body    json.RawMessage `json:"body,omitempty"`
+
+	// Attempts is the number of forward attempts it took to obtain this
+	// response. It is set by RetryClient and never sent over the wire.
+	Attempts int `json:"-"`
 }
 
 // Client defines the interface for forwarding events to an agent backend.
@@ -0,0 +1,105 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/youmna-rabie/claude-pod/internal/tracing"
+	"github.com/youmna-rabie/claude-pod/internal/types"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const maxResponseSize = 1 << 20 // 1 MB
+
+// StatusError is returned when the agent responds with a non-2xx status code.
+type StatusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	if e.Body == "" {
+		return fmt.Sprintf("agent returned status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("agent returned status %d: %s", e.StatusCode, e.Body)
+}
+
+// HTTPClient is a Client that POSTs envelopes as JSON to an agent runtime
+// and decodes the agent's reply into a Response.
+type HTTPClient struct {
+	url    string
+	client *http.Client
+}
+
+// NewHTTPClient creates an HTTPClient that sends envelopes to url.
+// A zero timeout means requests never time out on their own.
+func NewHTTPClient(url string, timeout time.Duration) *HTTPClient {
+	return &HTTPClient{
+		url:    url,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+// Forward sends the envelope to the agent and returns its decoded response.
+// Non-2xx replies are reported as *StatusError. Each call is traced as a
+// client span whose context is sent in the traceparent header.
+func (c *HTTPClient) Forward(ctx context.Context, envelope types.EventEnvelope) (resp Response, err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "POST agent", trace.WithSpanKind(trace.SpanKindClient))
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}()
+
+	payload, err := json.Marshal(envelope)
+	if err != nil {
+		return Response{}, fmt.Errorf("encoding envelope: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(payload))
+	if err != nil {
+		return Response{}, fmt.Errorf("building agent request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if envelope.RequestID != "" {
+		req.Header.Set("X-Request-ID", envelope.RequestID)
+	}
+	tracing.Inject(ctx, req.Header)
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return Response{}, fmt.Errorf("sending to agent: %w", err)
+	}
+	defer res.Body.Close()
+	span.SetAttributes(attribute.Int("http.response.status_code", res.StatusCode))
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, maxResponseSize+1))
+	if err != nil {
+		return Response{}, fmt.Errorf("reading agent response: %w", err)
+	}
+	if len(body) > maxResponseSize {
+		return Response{}, fmt.Errorf("agent response exceeds 1MB limit")
+	}
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return Response{}, &StatusError{
+			StatusCode: res.StatusCode,
+			Body:       string(bytes.TrimSpace(body)),
+		}
+	}
+
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return Response{}, fmt.Errorf("decoding agent response: %w", err)
+	}
+	return resp, nil
+}
@@ -0,0 +1,170 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/youmna-rabie/claude-pod/internal/tracing"
+	"github.com/youmna-rabie/claude-pod/internal/types"
+)
+
+func TestHTTPClient_ForwardsEnvelope(t *testing.T) {
+	envelope := newTestEnvelope()
+
+	var got types.EventEnvelope
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("method = %s, want POST", r.Method)
+		}
+		if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("Content-Type = %q, want application/json", ct)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decoding request: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"status":"ok","event_id":"abc","body":{"reply":"done"}}`))
+	}))
+	defer srv.Close()
+
+	client := NewHTTPClient(srv.URL, time.Second)
+	resp, err := client.Forward(context.Background(), envelope)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if resp.Status != "ok" {
+		t.Errorf("status = %q, want %q", resp.Status, "ok")
+	}
+	if resp.EventID != "abc" {
+		t.Errorf("event_id = %q, want %q", resp.EventID, "abc")
+	}
+	if string(resp.Body) != `{"reply":"done"}` {
+		t.Errorf("body = %s, want %s", resp.Body, `{"reply":"done"}`)
+	}
+	if got.Event.ID != envelope.Event.ID {
+		t.Errorf("agent received event %v, want %v", got.Event.ID, envelope.Event.ID)
+	}
+	if got.Channel != "slack" {
+		t.Errorf("agent received channel %q, want %q", got.Channel, "slack")
+	}
+}
+
+func TestHTTPClient_PropagatesRequestID(t *testing.T) {
+	var got string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get("X-Request-ID")
+		w.Write([]byte(`{"status":"ok"}`))
+	}))
+	defer srv.Close()
+
+	envelope := newTestEnvelope()
+	envelope.RequestID = "req-42"
+
+	client := NewHTTPClient(srv.URL, time.Second)
+	if _, err := client.Forward(context.Background(), envelope); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != "req-42" {
+		t.Errorf("X-Request-ID = %q, want %q", got, "req-42")
+	}
+}
+
+func TestHTTPClient_PropagatesTraceContext(t *testing.T) {
+	var got string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get("traceparent")
+		w.Write([]byte(`{"status":"ok"}`))
+	}))
+	defer srv.Close()
+
+	incoming := http.Header{}
+	incoming.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	ctx := tracing.Extract(context.Background(), incoming)
+
+	client := NewHTTPClient(srv.URL, time.Second)
+	if _, err := client.Forward(ctx, newTestEnvelope()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(got, "00-4bf92f3577b34da6a3ce929d0e0e4736-") {
+		t.Errorf("traceparent = %q, want the caller's trace", got)
+	}
+}
+
+func TestHTTPClient_ContextCancelled(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	client := NewHTTPClient(srv.URL, time.Second)
+	_, err := client.Forward(ctx, newTestEnvelope())
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("error = %v, want context.Canceled", err)
+	}
+}
+
+func TestHTTPClient_NonSuccessStatus(t *testing.T) {
+	for _, code := range []int{http.StatusBadRequest, http.StatusInternalServerError, http.StatusServiceUnavailable} {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "nope", code)
+		}))
+
+		client := NewHTTPClient(srv.URL, time.Second)
+		_, err := client.Forward(context.Background(), newTestEnvelope())
+		srv.Close()
+
+		var statusErr *StatusError
+		if !errors.As(err, &statusErr) {
+			t.Fatalf("status %d: error = %v, want *StatusError", code, err)
+		}
+		if statusErr.StatusCode != code {
+			t.Errorf("StatusCode = %d, want %d", statusErr.StatusCode, code)
+		}
+		if statusErr.Body != "nope" {
+			t.Errorf("Body = %q, want %q", statusErr.Body, "nope")
+		}
+	}
+}
+
+func TestHTTPClient_InvalidResponseJSON(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("not json"))
+	}))
+	defer srv.Close()
+
+	client := NewHTTPClient(srv.URL, time.Second)
+	if _, err := client.Forward(context.Background(), newTestEnvelope()); err == nil {
+		t.Fatal("expected error for invalid response JSON")
+	}
+}
+
+func TestHTTPClient_Timeout(t *testing.T) {
+	done := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+		}
+	}))
+	defer srv.Close()
+	defer close(done)
+
+	client := NewHTTPClient(srv.URL, 20*time.Millisecond)
+	if _, err := client.Forward(context.Background(), newTestEnvelope()); err == nil {
+		t.Fatal("expected timeout error")
+	}
+}
+
+func TestHTTPClient_ImplementsClientInterface(t *testing.T) {
+	var _ Client = (*HTTPClient)(nil)
+}
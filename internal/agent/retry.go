@@ -0,0 +1,156 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	"github.com/youmna-rabie/claude-pod/internal/types"
+)
+
+// RetryPolicy controls how RetryClient retries failed forwards.
+type RetryPolicy struct {
+	MaxAttempts int           // total attempts including the first; values < 1 mean 1
+	BaseBackoff time.Duration // delay before the second attempt
+	MaxBackoff  time.Duration // upper bound for any single delay
+	Jitter      float64       // fraction (0–1) of each delay that is randomized
+}
+
+// RetryError is returned by RetryClient when forwarding ultimately fails.
+// It records how many attempts were made before giving up.
+type RetryError struct {
+	Attempts int
+	Err      error
+}
+
+func (e *RetryError) Error() string {
+	return fmt.Sprintf("after %d attempt(s): %v", e.Attempts, e.Err)
+}
+
+func (e *RetryError) Unwrap() error {
+	return e.Err
+}
+
+// RetryClient wraps a Client and retries retryable failures with
+// exponential backoff and jitter.
+type RetryClient struct {
+	next   Client
+	policy RetryPolicy
+	logger *slog.Logger
+}
+
+// NewRetryClient wraps next with the given retry policy.
+func NewRetryClient(next Client, policy RetryPolicy, logger *slog.Logger) *RetryClient {
+	if policy.MaxAttempts < 1 {
+		policy.MaxAttempts = 1
+	}
+	return &RetryClient{next: next, policy: policy, logger: logger}
+}
+
+// Forward calls the wrapped client until it succeeds, returns a
+// non-retryable error, the context is done, or attempts are exhausted.
+// The number of attempts is reported in Response.Attempts or *RetryError.
+func (c *RetryClient) Forward(ctx context.Context, envelope types.EventEnvelope) (Response, error) {
+	var lastErr error
+	for attempt := 1; attempt <= c.policy.MaxAttempts; attempt++ {
+		resp, err := c.next.Forward(ctx, envelope)
+		if err == nil {
+			resp.Attempts = attempt
+			return resp, nil
+		}
+		lastErr = err
+
+		if attempt == c.policy.MaxAttempts || !IsRetryable(err) {
+			return Response{}, &RetryError{Attempts: attempt, Err: err}
+		}
+
+		delay := c.policy.backoff(attempt)
+		c.logger.Warn("agent forward failed, retrying",
+			"event_id", envelope.Event.ID,
+			"attempt", attempt,
+			"delay_ms", delay.Milliseconds(),
+			"error", err,
+		)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return Response{}, &RetryError{Attempts: attempt, Err: errors.Join(err, ctx.Err())}
+		case <-timer.C:
+		}
+	}
+	return Response{}, &RetryError{Attempts: c.policy.MaxAttempts, Err: lastErr}
+}
+
+// backoff returns the delay to wait after the given (1-based) attempt.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	d := p.BaseBackoff
+	for i := 1; i < attempt && (p.MaxBackoff <= 0 || d < p.MaxBackoff); i++ {
+		d *= 2
+	}
+	if p.MaxBackoff > 0 && d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	if p.Jitter > 0 && d > 0 {
+		d -= time.Duration(rand.Float64() * p.Jitter * float64(d))
+	}
+	return d
+}
+
+// IsRetryable reports whether a forward error is worth retrying.
+// Timeouts, refused or reset connections, 5xx responses, 408 and 429 are
+// retryable; other 4xx responses, cancellations and unknown errors are not.
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		switch {
+		case statusErr.StatusCode >= 500:
+			return true
+		case statusErr.StatusCode == http.StatusRequestTimeout,
+			statusErr.StatusCode == http.StatusTooManyRequests:
+			return true
+		default:
+			return false
+		}
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET)
+}
+
+// Attempts returns the number of forward attempts recorded in the result of
+// a Forward call. Clients that do not retry count as a single attempt, and a
+// forward refused by an open circuit breaker as none.
+func Attempts(resp Response, err error) int {
+	if err != nil {
+		if errors.Is(err, ErrCircuitOpen) {
+			return 0
+		}
+		var retryErr *RetryError
+		if errors.As(err, &retryErr) {
+			return retryErr.Attempts
+		}
+		return 1
+	}
+	if resp.Attempts > 0 {
+		return resp.Attempts
+	}
+	return 1
+}
@@ -0,0 +1,169 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/youmna-rabie/claude-pod/internal/types"
+)
+
+// flakyClient fails with err for the first failures calls, then succeeds.
+type flakyClient struct {
+	failures int
+	err      error
+	calls    int
+}
+
+func (f *flakyClient) Forward(_ context.Context, envelope types.EventEnvelope) (Response, error) {
+	f.calls++
+	if f.calls <= f.failures {
+		return Response{}, f.err
+	}
+	return Response{Status: "ok", EventID: envelope.Event.ID.String()}, nil
+}
+
+func quietLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func fastPolicy(attempts int) RetryPolicy {
+	return RetryPolicy{MaxAttempts: attempts, BaseBackoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond}
+}
+
+func TestRetryClient_SucceedsAfterRetryableFailures(t *testing.T) {
+	next := &flakyClient{failures: 2, err: &StatusError{StatusCode: http.StatusServiceUnavailable}}
+	client := NewRetryClient(next, fastPolicy(3), quietLogger())
+
+	resp, err := client.Forward(context.Background(), newTestEnvelope())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if next.calls != 3 {
+		t.Errorf("calls = %d, want 3", next.calls)
+	}
+	if got := Attempts(resp, err); got != 3 {
+		t.Errorf("Attempts = %d, want 3", got)
+	}
+}
+
+func TestRetryClient_GivesUpAfterMaxAttempts(t *testing.T) {
+	next := &flakyClient{failures: 10, err: &StatusError{StatusCode: http.StatusBadGateway}}
+	client := NewRetryClient(next, fastPolicy(4), quietLogger())
+
+	resp, err := client.Forward(context.Background(), newTestEnvelope())
+	if err == nil {
+		t.Fatal("expected error after exhausting attempts")
+	}
+	if next.calls != 4 {
+		t.Errorf("calls = %d, want 4", next.calls)
+	}
+	if got := Attempts(resp, err); got != 4 {
+		t.Errorf("Attempts = %d, want 4", got)
+	}
+
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) {
+		t.Errorf("error %v should wrap *StatusError", err)
+	}
+}
+
+func TestRetryClient_DoesNotRetryClientErrors(t *testing.T) {
+	next := &flakyClient{failures: 10, err: &StatusError{StatusCode: http.StatusBadRequest}}
+	client := NewRetryClient(next, fastPolicy(5), quietLogger())
+
+	resp, err := client.Forward(context.Background(), newTestEnvelope())
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if next.calls != 1 {
+		t.Errorf("calls = %d, want 1", next.calls)
+	}
+	if got := Attempts(resp, err); got != 1 {
+		t.Errorf("Attempts = %d, want 1", got)
+	}
+}
+
+func TestRetryClient_StopsWhenContextCancelled(t *testing.T) {
+	next := &flakyClient{failures: 10, err: syscall.ECONNREFUSED}
+	policy := RetryPolicy{MaxAttempts: 5, BaseBackoff: time.Hour, MaxBackoff: time.Hour}
+	client := NewRetryClient(next, policy, quietLogger())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := client.Forward(ctx, newTestEnvelope())
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("error = %v, want context.DeadlineExceeded", err)
+	}
+	if next.calls != 1 {
+		t.Errorf("calls = %d, want 1", next.calls)
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"500", &StatusError{StatusCode: 500}, true},
+		{"503", &StatusError{StatusCode: 503}, true},
+		{"408", &StatusError{StatusCode: 408}, true},
+		{"429", &StatusError{StatusCode: 429}, true},
+		{"400", &StatusError{StatusCode: 400}, false},
+		{"404", &StatusError{StatusCode: 404}, false},
+		{"connection refused", fmt.Errorf("dial: %w", syscall.ECONNREFUSED), true},
+		{"connection reset", fmt.Errorf("read: %w", syscall.ECONNRESET), true},
+		{"deadline exceeded", context.DeadlineExceeded, true},
+		{"cancelled", context.Canceled, false},
+		{"other", errors.New("boom"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsRetryable(tt.err); got != tt.want {
+				t.Errorf("IsRetryable(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	p := RetryPolicy{BaseBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+
+	want := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond, time.Second, time.Second}
+	for i, w := range want {
+		if got := p.backoff(i + 1); got != w {
+			t.Errorf("backoff(%d) = %v, want %v", i+1, got, w)
+		}
+	}
+
+	p.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		d := p.backoff(3)
+		if d < 200*time.Millisecond || d > 400*time.Millisecond {
+			t.Fatalf("jittered backoff = %v, want within [200ms, 400ms]", d)
+		}
+	}
+}
+
+func TestAttempts_NonRetryingClient(t *testing.T) {
+	if got := Attempts(Response{Status: "ok"}, nil); got != 1 {
+		t.Errorf("Attempts(success) = %d, want 1", got)
+	}
+	if got := Attempts(Response{}, errors.New("boom")); got != 1 {
+		t.Errorf("Attempts(failure) = %d, want 1", got)
+	}
+}
+
+func TestRetryClient_ImplementsClientInterface(t *testing.T) {
+	var _ Client = (*RetryClient)(nil)
+}
@@ -0,0 +1,155 @@
+package channel
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/youmna-rabie/claude-pod/internal/types"
+)
+
+// alertmanagerPayload is the Alertmanager webhook payload, version 4.
+type alertmanagerPayload struct {
+	Version         string              `json:"version"`
+	GroupKey        string              `json:"groupKey"`
+	TruncatedAlerts int                 `json:"truncatedAlerts"`
+	Status          string              `json:"status"`
+	Receiver        string              `json:"receiver"`
+	GroupLabels     map[string]string   `json:"groupLabels"`
+	Alerts          []alertmanagerAlert `json:"alerts"`
+}
+
+type alertmanagerAlert struct {
+	Status      string            `json:"status"`
+	Labels      map[string]string `json:"labels"`
+	Fingerprint string            `json:"fingerprint"`
+}
+
+// AlertmanagerChannel accepts Prometheus Alertmanager webhooks (payload
+// version 4), authenticated by bearer token, basic auth, or either when
+// both are configured.
+type AlertmanagerChannel struct {
+	name     string
+	token    string
+	username string
+	password string
+}
+
+// NewAlertmanagerChannel creates an AlertmanagerChannel. An empty token and
+// username leave it unauthenticated.
+func NewAlertmanagerChannel(name, token, username, password string) *AlertmanagerChannel {
+	return &AlertmanagerChannel{name: name, token: token, username: username, password: password}
+}
+
+func (a *AlertmanagerChannel) Name() string {
+	return a.name
+}
+
+func (a *AlertmanagerChannel) ValidateRequest(r *http.Request) error {
+	if r.Method != http.MethodPost {
+		return fmt.Errorf("method %s not allowed, expected POST", r.Method)
+	}
+	if mt := mediaType(r); mt != "application/json" {
+		return fmt.Errorf("unsupported Content-Type %q, expected application/json", r.Header.Get("Content-Type"))
+	}
+	if a.token == "" && a.username == "" {
+		return nil
+	}
+
+	if a.token != "" {
+		if tok, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok && equalSecret(tok, a.token) {
+			return nil
+		}
+	}
+	if a.username != "" {
+		if user, pass, ok := r.BasicAuth(); ok && equalSecret(user, a.username) && equalSecret(pass, a.password) {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid or missing authorization")
+}
+
+// ParseRequest checks the payload against the version 4 schema and records
+// the group key, status, receiver, group labels and alert fingerprints in
+// the event's metadata.
+func (a *AlertmanagerChannel) ParseRequest(r *http.Request) (*types.Event, error) {
+	body, err := readBody(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var p alertmanagerPayload
+	if err := json.Unmarshal(body, &p); err != nil {
+		return nil, fmt.Errorf("request body is not a valid Alertmanager payload: %w", err)
+	}
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+
+	return &types.Event{
+		ID:        uuid.New(),
+		ChannelID: a.name,
+		RawBody:   json.RawMessage(body),
+		Headers:   extractHeaders(r),
+		Metadata:  p.metadata(),
+		Timestamp: time.Now(),
+		Status:    types.EventStatusReceived,
+	}, nil
+}
+
+func (p *alertmanagerPayload) validate() error {
+	if p.Version != "4" {
+		return fmt.Errorf("unsupported Alertmanager payload version %q, expected 4", p.Version)
+	}
+	if p.GroupKey == "" {
+		return fmt.Errorf("payload is missing groupKey")
+	}
+	if p.Status != "firing" && p.Status != "resolved" {
+		return fmt.Errorf("payload status must be firing or resolved, got %q", p.Status)
+	}
+	if len(p.Alerts) == 0 {
+		return fmt.Errorf("payload has no alerts")
+	}
+	for i, alert := range p.Alerts {
+		if alert.Status != "firing" && alert.Status != "resolved" {
+			return fmt.Errorf("alerts[%d].status must be firing or resolved, got %q", i, alert.Status)
+		}
+		if alert.Fingerprint == "" {
+			return fmt.Errorf("alerts[%d] is missing fingerprint", i)
+		}
+		if len(alert.Labels) == 0 {
+			return fmt.Errorf("alerts[%d] has no labels", i)
+		}
+	}
+	return nil
+}
+
+// metadata flattens the fields an agent routes on. Group labels become
+// alertmanager.group_labels.<name> and fingerprints a comma-separated list
+// in alert order.
+func (p *alertmanagerPayload) metadata() map[string]string {
+	md := map[string]string{
+		"alertmanager.group_key": p.GroupKey,
+		"alertmanager.status":    p.Status,
+		"alertmanager.receiver":  p.Receiver,
+	}
+	for name, value := range p.GroupLabels {
+		md["alertmanager.group_labels."+name] = value
+	}
+	fingerprints := make([]string, 0, len(p.Alerts))
+	for _, alert := range p.Alerts {
+		if !slices.Contains(fingerprints, alert.Fingerprint) {
+			fingerprints = append(fingerprints, alert.Fingerprint)
+		}
+	}
+	md["alertmanager.fingerprints"] = strings.Join(fingerprints, ",")
+	if p.TruncatedAlerts > 0 {
+		md["alertmanager.truncated_alerts"] = strconv.Itoa(p.TruncatedAlerts)
+	}
+	return md
+}
@@ -0,0 +1,120 @@
+package channel
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const alertmanagerBody = `{
+  "version": "4",
+  "groupKey": "{}:{alertname=\"HighCPU\"}",
+  "truncatedAlerts": 0,
+  "status": "firing",
+  "receiver": "claude-pod",
+  "groupLabels": {"alertname": "HighCPU", "cluster": "prod"},
+  "commonLabels": {"alertname": "HighCPU"},
+  "commonAnnotations": {},
+  "externalURL": "http://alertmanager:9093",
+  "alerts": [
+    {"status": "firing", "labels": {"alertname": "HighCPU", "instance": "a"}, "annotations": {},
+     "startsAt": "2024-01-01T00:00:00Z", "endsAt": "0001-01-01T00:00:00Z", "fingerprint": "f1"},
+    {"status": "resolved", "labels": {"alertname": "HighCPU", "instance": "b"}, "annotations": {},
+     "startsAt": "2024-01-01T00:00:00Z", "endsAt": "2024-01-01T01:00:00Z", "fingerprint": "f2"}
+  ]
+}`
+
+func newAlertmanagerRequest(body string) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/json; charset=utf-8")
+	return r
+}
+
+func TestAlertmanagerChannel_ValidateRequest_Auth(t *testing.T) {
+	tests := []struct {
+		name       string
+		token      string
+		user, pass string
+		setup      func(r *http.Request)
+		wantErr    bool
+	}{
+		{name: "no auth configured", setup: func(*http.Request) {}},
+		{name: "bearer ok", token: "tok", setup: func(r *http.Request) { r.Header.Set("Authorization", "Bearer tok") }},
+		{name: "bearer wrong", token: "tok", setup: func(r *http.Request) { r.Header.Set("Authorization", "Bearer nope") }, wantErr: true},
+		{name: "bearer missing", token: "tok", setup: func(*http.Request) {}, wantErr: true},
+		{name: "basic ok", user: "am", pass: "pw", setup: func(r *http.Request) { r.SetBasicAuth("am", "pw") }},
+		{name: "basic wrong password", user: "am", pass: "pw", setup: func(r *http.Request) { r.SetBasicAuth("am", "x") }, wantErr: true},
+		{name: "either accepts basic", token: "tok", user: "am", pass: "pw", setup: func(r *http.Request) { r.SetBasicAuth("am", "pw") }},
+		{name: "either accepts bearer", token: "tok", user: "am", pass: "pw", setup: func(r *http.Request) { r.Header.Set("Authorization", "Bearer tok") }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ch := NewAlertmanagerChannel("am", tt.token, tt.user, tt.pass)
+			r := newAlertmanagerRequest(alertmanagerBody)
+			tt.setup(r)
+			err := ch.ValidateRequest(r)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateRequest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAlertmanagerChannel_ValidateRequest_ContentType(t *testing.T) {
+	ch := NewAlertmanagerChannel("am", "", "", "")
+	r := newAlertmanagerRequest(alertmanagerBody)
+	r.Header.Set("Content-Type", "text/plain")
+	if err := ch.ValidateRequest(r); err == nil {
+		t.Fatal("text/plain should be rejected")
+	}
+}
+
+func TestAlertmanagerChannel_ParseRequest(t *testing.T) {
+	ch := NewAlertmanagerChannel("am", "", "", "")
+	ev, err := ch.ParseRequest(newAlertmanagerRequest(alertmanagerBody))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := map[string]string{
+		"alertmanager.group_key":              `{}:{alertname="HighCPU"}`,
+		"alertmanager.status":                 "firing",
+		"alertmanager.receiver":               "claude-pod",
+		"alertmanager.group_labels.alertname": "HighCPU",
+		"alertmanager.group_labels.cluster":   "prod",
+		"alertmanager.fingerprints":           "f1,f2",
+	}
+	for k, v := range want {
+		if ev.Metadata[k] != v {
+			t.Errorf("metadata[%q] = %q, want %q", k, ev.Metadata[k], v)
+		}
+	}
+	if _, ok := ev.Metadata["alertmanager.truncated_alerts"]; ok {
+		t.Error("truncated_alerts set although no alerts were truncated")
+	}
+	if ev.ChannelID != "am" || string(ev.RawBody) != alertmanagerBody {
+		t.Errorf("event = %+v, want the raw payload on channel am", ev)
+	}
+}
+
+func TestAlertmanagerChannel_ParseRequest_Schema(t *testing.T) {
+	tests := map[string]string{
+		"not json":      `{`,
+		"wrong version": strings.Replace(alertmanagerBody, `"version": "4"`, `"version": "3"`, 1),
+		"no group key":  strings.Replace(alertmanagerBody, `"groupKey"`, `"otherKey"`, 1),
+		"bad status": strings.Replace(alertmanagerBody, `"status": "firing",
+  "receiver"`, `"status": "pending",
+  "receiver"`, 1),
+		"no fingerprint": strings.Replace(alertmanagerBody, `"fingerprint": "f2"`, `"fingerprint": ""`, 1),
+		"no alerts":      `{"version":"4","groupKey":"k","status":"firing","alerts":[]}`,
+	}
+	for name, body := range tests {
+		t.Run(name, func(t *testing.T) {
+			ch := NewAlertmanagerChannel("am", "", "", "")
+			if _, err := ch.ParseRequest(newAlertmanagerRequest(body)); err == nil {
+				t.Fatal("expected a schema error, got nil")
+			}
+		})
+	}
+}
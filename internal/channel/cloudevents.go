@@ -0,0 +1,187 @@
+package channel
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/youmna-rabie/claude-pod/internal/types"
+)
+
+// CloudEvents HTTP content types. A request with any other content type is
+// in binary mode, with the attributes in ce-* headers and the data as body.
+const (
+	ceStructured = "application/cloudevents+json"
+	ceBatch      = "application/cloudevents-batch+json"
+)
+
+// ceRequired are the attributes every CloudEvents 1.0 event must have.
+var ceRequired = []string{"specversion", "id", "source", "type"}
+
+// CloudEventsChannel accepts CloudEvents 1.0 over HTTP in structured, binary
+// and batch content modes. Every event is stored in structured form, so the
+// agent sees the same shape whichever mode the sender used.
+type CloudEventsChannel struct {
+	name      string
+	authToken string
+}
+
+// NewCloudEventsChannel creates a CloudEventsChannel. If authToken is set,
+// requests must carry it as a Bearer token.
+func NewCloudEventsChannel(name, authToken string) *CloudEventsChannel {
+	return &CloudEventsChannel{name: name, authToken: authToken}
+}
+
+func (c *CloudEventsChannel) Name() string {
+	return c.name
+}
+
+func (c *CloudEventsChannel) ValidateRequest(r *http.Request) error {
+	if r.Method != http.MethodPost {
+		return fmt.Errorf("method %s not allowed, expected POST", r.Method)
+	}
+	switch mediaType(r) {
+	case ceStructured, ceBatch:
+	default:
+		if r.Header.Get("Ce-Specversion") == "" {
+			return fmt.Errorf("unsupported Content-Type %q, expected %s, %s or a binary-mode request with ce-specversion",
+				r.Header.Get("Content-Type"), ceStructured, ceBatch)
+		}
+	}
+	if c.authToken != "" && !equalSecret(r.Header.Get("Authorization"), "Bearer "+c.authToken) {
+		return fmt.Errorf("invalid or missing authorization token")
+	}
+	return nil
+}
+
+// IsBatch reports whether r is in batch content mode.
+func (c *CloudEventsChannel) IsBatch(r *http.Request) bool {
+	return mediaType(r) == ceBatch
+}
+
+// ParseRequest parses a structured or binary mode request. The event's id,
+// source, type and, if set, subject are recorded in its metadata.
+func (c *CloudEventsChannel) ParseRequest(r *http.Request) (*types.Event, error) {
+	body, err := readBody(r)
+	if err != nil {
+		return nil, err
+	}
+	if mediaType(r) != ceStructured {
+		if body, err = structuredFromBinary(r, body); err != nil {
+			return nil, err
+		}
+	}
+	return c.parseEvent(body, extractHeaders(r))
+}
+
+// ParseBatch parses a batch mode request, a JSON array of structured events.
+func (c *CloudEventsChannel) ParseBatch(r *http.Request) ([]*types.Event, error) {
+	body, err := readBody(r)
+	if err != nil {
+		return nil, err
+	}
+	var raws []json.RawMessage
+	if err := json.Unmarshal(body, &raws); err != nil {
+		return nil, fmt.Errorf("batch is not a JSON array: %w", err)
+	}
+	if len(raws) == 0 {
+		return nil, fmt.Errorf("batch contains no events")
+	}
+
+	headers := extractHeaders(r)
+	events := make([]*types.Event, 0, len(raws))
+	for i, raw := range raws {
+		evt, err := c.parseEvent(raw, headers)
+		if err != nil {
+			return nil, fmt.Errorf("events[%d]: %w", i, err)
+		}
+		events = append(events, evt)
+	}
+	return events, nil
+}
+
+// parseEvent checks a structured event's required attributes and builds the
+// gateway event around it.
+func (c *CloudEventsChannel) parseEvent(body []byte, headers map[string]string) (*types.Event, error) {
+	var attrs map[string]json.RawMessage
+	if err := json.Unmarshal(body, &attrs); err != nil {
+		return nil, fmt.Errorf("event is not a JSON object: %w", err)
+	}
+
+	str := func(name string) string {
+		var v string
+		_ = json.Unmarshal(attrs[name], &v)
+		return v
+	}
+	for _, name := range ceRequired {
+		if str(name) == "" {
+			return nil, fmt.Errorf("missing required attribute %q", name)
+		}
+	}
+	if v := str("specversion"); v != "1.0" {
+		return nil, fmt.Errorf("unsupported specversion %q, expected 1.0", v)
+	}
+
+	md := map[string]string{
+		"cloudevents.id":     str("id"),
+		"cloudevents.source": str("source"),
+		"cloudevents.type":   str("type"),
+	}
+	if subject := str("subject"); subject != "" {
+		md["cloudevents.subject"] = subject
+	}
+
+	return &types.Event{
+		ID:        uuid.New(),
+		ChannelID: c.name,
+		RawBody:   json.RawMessage(body),
+		Headers:   headers,
+		Metadata:  md,
+		Timestamp: time.Now(),
+		Status:    types.EventStatusReceived,
+	}, nil
+}
+
+// structuredFromBinary builds the structured form of a binary mode event
+// from its ce-* headers and body. JSON data is kept as data, anything else
+// is base64-encoded as data_base64.
+func structuredFromBinary(r *http.Request, body []byte) ([]byte, error) {
+	event := make(map[string]any)
+	for name := range r.Header {
+		attr, ok := strings.CutPrefix(strings.ToLower(name), "ce-")
+		if !ok || attr == "" {
+			continue
+		}
+		value := r.Header.Get(name)
+		if decoded, err := url.PathUnescape(value); err == nil {
+			value = decoded
+		}
+		event[attr] = value
+	}
+
+	if ct := r.Header.Get("Content-Type"); ct != "" {
+		event["datacontenttype"] = ct
+	}
+	if len(body) > 0 {
+		if isJSONMediaType(mediaType(r)) && json.Valid(body) {
+			event["data"] = json.RawMessage(body)
+		} else {
+			event["data_base64"] = body // encoding/json writes []byte as base64
+		}
+	}
+
+	structured, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("encoding binary mode event: %w", err)
+	}
+	return structured, nil
+}
+
+// isJSONMediaType reports whether mt is application/json or a +json type.
+func isJSONMediaType(mt string) bool {
+	return mt == "application/json" || strings.HasSuffix(mt, "+json")
+}
@@ -0,0 +1,170 @@
+package channel
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const ceEvent = `{"specversion":"1.0","id":"A234-1234-1234","source":"/deploys/api","type":"com.example.deploy.finished","subject":"api-v42","data":{"ok":true}}`
+
+func newCERequest(contentType, body string) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	r.Header.Set("Content-Type", contentType)
+	return r
+}
+
+func newBinaryCERequest(contentType, body string) *http.Request {
+	r := newCERequest(contentType, body)
+	r.Header.Set("ce-specversion", "1.0")
+	r.Header.Set("ce-id", "B1")
+	r.Header.Set("ce-source", "/builds")
+	r.Header.Set("ce-type", "com.example.build.failed")
+	r.Header.Set("ce-subject", "main%20branch")
+	return r
+}
+
+func TestCloudEventsChannel_ValidateRequest(t *testing.T) {
+	ch := NewCloudEventsChannel("ce", "")
+
+	for name, r := range map[string]*http.Request{
+		"structured": newCERequest("application/cloudevents+json; charset=utf-8", ceEvent),
+		"batch":      newCERequest("application/cloudevents-batch+json", "["+ceEvent+"]"),
+		"binary":     newBinaryCERequest("application/json", `{}`),
+	} {
+		if err := ch.ValidateRequest(r); err != nil {
+			t.Errorf("%s: unexpected error: %v", name, err)
+		}
+	}
+
+	if err := ch.ValidateRequest(newCERequest("application/json", ceEvent)); err == nil {
+		t.Error("plain JSON without ce-specversion should fail")
+	}
+
+	authed := NewCloudEventsChannel("ce", "tok")
+	r := newCERequest("application/cloudevents+json", ceEvent)
+	if err := authed.ValidateRequest(r); err == nil {
+		t.Error("request without the token should fail")
+	}
+	r.Header.Set("Authorization", "Bearer tok")
+	if err := authed.ValidateRequest(r); err != nil {
+		t.Errorf("request with the token should pass: %v", err)
+	}
+}
+
+func TestCloudEventsChannel_Structured(t *testing.T) {
+	ch := NewCloudEventsChannel("ce", "")
+	r := newCERequest("application/cloudevents+json", ceEvent)
+	if ch.IsBatch(r) {
+		t.Fatal("structured request reported as a batch")
+	}
+	ev, err := ch.ParseRequest(r)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := map[string]string{
+		"cloudevents.id":      "A234-1234-1234",
+		"cloudevents.source":  "/deploys/api",
+		"cloudevents.type":    "com.example.deploy.finished",
+		"cloudevents.subject": "api-v42",
+	}
+	for k, v := range want {
+		if ev.Metadata[k] != v {
+			t.Errorf("metadata[%q] = %q, want %q", k, ev.Metadata[k], v)
+		}
+	}
+	if string(ev.RawBody) != ceEvent {
+		t.Errorf("raw_body = %s, want the event as sent", ev.RawBody)
+	}
+}
+
+func TestCloudEventsChannel_Binary(t *testing.T) {
+	ch := NewCloudEventsChannel("ce", "")
+
+	ev, err := ch.ParseRequest(newBinaryCERequest("application/json", `{"job":7}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var got map[string]any
+	if err := json.Unmarshal(ev.RawBody, &got); err != nil {
+		t.Fatalf("raw_body is not JSON: %v", err)
+	}
+	if got["id"] != "B1" || got["subject"] != "main branch" || got["datacontenttype"] != "application/json" {
+		t.Errorf("raw_body = %v, want attributes from ce-* headers", got)
+	}
+	if data, _ := got["data"].(map[string]any); data["job"] != float64(7) {
+		t.Errorf("data = %v, want the JSON body", got["data"])
+	}
+	if ev.Metadata["cloudevents.type"] != "com.example.build.failed" || ev.Metadata["cloudevents.subject"] != "main branch" {
+		t.Errorf("metadata = %v", ev.Metadata)
+	}
+
+	ev, err = ch.ParseRequest(newBinaryCERequest("text/plain", "build log"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got = nil
+	if err := json.Unmarshal(ev.RawBody, &got); err != nil {
+		t.Fatalf("raw_body is not JSON: %v", err)
+	}
+	if got["data_base64"] != "YnVpbGQgbG9n" {
+		t.Errorf("data_base64 = %v, want the body base64-encoded", got["data_base64"])
+	}
+}
+
+func TestCloudEventsChannel_RequiredAttributes(t *testing.T) {
+	ch := NewCloudEventsChannel("ce", "")
+	tests := map[string]string{
+		"missing id":     `{"specversion":"1.0","source":"/s","type":"t"}`,
+		"missing source": `{"specversion":"1.0","id":"1","type":"t"}`,
+		"missing type":   `{"specversion":"1.0","id":"1","source":"/s"}`,
+		"old version":    `{"specversion":"0.3","id":"1","source":"/s","type":"t"}`,
+		"not an object":  `["x"]`,
+	}
+	for name, body := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := ch.ParseRequest(newCERequest("application/cloudevents+json", body)); err == nil {
+				t.Fatal("expected a parse error, got nil")
+			}
+		})
+	}
+
+	r := newBinaryCERequest("application/json", `{}`)
+	r.Header.Del("ce-source")
+	if _, err := ch.ParseRequest(r); err == nil {
+		t.Error("binary request without ce-source should fail")
+	}
+}
+
+func TestCloudEventsChannel_Batch(t *testing.T) {
+	ch := NewCloudEventsChannel("ce", "")
+	second := `{"specversion":"1.0","id":"2","source":"/s","type":"t"}`
+
+	r := newCERequest("application/cloudevents-batch+json", "["+ceEvent+","+second+"]")
+	if !ch.IsBatch(r) {
+		t.Fatal("batch request not reported as a batch")
+	}
+	events, err := ch.ParseBatch(r)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) != 2 || events[1].Metadata["cloudevents.id"] != "2" || string(events[1].RawBody) != second {
+		t.Fatalf("events = %+v, want both events in order", events)
+	}
+	if events[0].ID == events[1].ID {
+		t.Error("batch events share an ID")
+	}
+
+	for name, body := range map[string]string{
+		"empty":         "[]",
+		"not an array":  ceEvent,
+		"invalid event": "[" + ceEvent + `,{"id":"3"}]`,
+	} {
+		if _, err := ch.ParseBatch(newCERequest("application/cloudevents-batch+json", body)); err == nil {
+			t.Errorf("%s: expected a parse error, got nil", name)
+		}
+	}
+}
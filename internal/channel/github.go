@@ -0,0 +1,113 @@
+package channel
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/youmna-rabie/claude-pod/internal/types"
+)
+
+// GitHubChannel accepts GitHub webhooks signed with X-Hub-Signature-256. It
+// answers ping events itself and ignores event types it was not configured
+// to accept.
+type GitHubChannel struct {
+	name   string
+	secret string
+	events []string
+}
+
+// NewGitHubChannel creates a GitHubChannel verifying signatures with secret.
+// events lists the X-GitHub-Event types to accept; empty accepts all.
+func NewGitHubChannel(name, secret string, events []string) *GitHubChannel {
+	return &GitHubChannel{name: name, secret: secret, events: events}
+}
+
+func (g *GitHubChannel) Name() string {
+	return g.name
+}
+
+func (g *GitHubChannel) ValidateRequest(r *http.Request) error {
+	if r.Method != http.MethodPost {
+		return fmt.Errorf("method %s not allowed, expected POST", r.Method)
+	}
+	if mt := mediaType(r); mt != "application/json" {
+		return fmt.Errorf("unsupported Content-Type %q, expected application/json", r.Header.Get("Content-Type"))
+	}
+	if r.Header.Get("X-GitHub-Event") == "" {
+		return fmt.Errorf("missing X-GitHub-Event header")
+	}
+
+	body, err := readBody(r)
+	if err != nil {
+		return err
+	}
+	hexSig, ok := strings.CutPrefix(r.Header.Get("X-Hub-Signature-256"), "sha256=")
+	if !ok {
+		return fmt.Errorf("missing X-Hub-Signature-256 header")
+	}
+	sig, err := hex.DecodeString(hexSig)
+	if err != nil || !validHMAC(sha256.New, g.secret, body, sig) {
+		return fmt.Errorf("invalid X-Hub-Signature-256 signature")
+	}
+	return nil
+}
+
+// ParseRequest records the event type, delivery ID, and the payload's action
+// and repository, if any, in the event's metadata. Pings and filtered-out
+// event types are answered with a *types.Reply.
+func (g *GitHubChannel) ParseRequest(r *http.Request) (*types.Event, error) {
+	body, err := readBody(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var payload struct {
+		Action     string `json:"action"`
+		Repository struct {
+			FullName string `json:"full_name"`
+		} `json:"repository"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("request body is not valid JSON: %w", err)
+	}
+
+	eventType := r.Header.Get("X-GitHub-Event")
+	delivery := r.Header.Get("X-GitHub-Delivery")
+	if eventType == "ping" {
+		return nil, &types.Reply{Status: http.StatusOK, Body: map[string]string{"status": "pong"}}
+	}
+	if len(g.events) > 0 && !slices.Contains(g.events, eventType) {
+		return nil, &types.Reply{Status: http.StatusOK, Body: map[string]string{
+			"status": "ignored",
+			"event":  eventType,
+		}}
+	}
+
+	md := map[string]string{
+		"github.event":    eventType,
+		"github.delivery": delivery,
+	}
+	if payload.Action != "" {
+		md["github.action"] = payload.Action
+	}
+	if payload.Repository.FullName != "" {
+		md["github.repository"] = payload.Repository.FullName
+	}
+
+	return &types.Event{
+		ID:        uuid.New(),
+		ChannelID: g.name,
+		RawBody:   json.RawMessage(body),
+		Headers:   extractHeaders(r),
+		Metadata:  md,
+		Timestamp: time.Now(),
+		Status:    types.EventStatusReceived,
+	}, nil
+}
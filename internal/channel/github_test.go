@@ -0,0 +1,96 @@
+package channel
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/youmna-rabie/claude-pod/internal/types"
+)
+
+const githubBody = `{"action":"opened","number":7,"repository":{"full_name":"octo/repo"}}`
+
+func newGitHubRequest(event, body, secret string) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/json")
+	r.Header.Set("X-GitHub-Event", event)
+	r.Header.Set("X-GitHub-Delivery", "72d3162e-cc78-11e3-81ab-4c9367dc0958")
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(body))
+	r.Header.Set("X-Hub-Signature-256", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	return r
+}
+
+func TestGitHubChannel_ValidateRequest(t *testing.T) {
+	ch := NewGitHubChannel("gh", "s3cret", nil)
+
+	r := newGitHubRequest("pull_request", githubBody, "s3cret")
+	if err := ch.ValidateRequest(r); err != nil {
+		t.Fatalf("correctly signed request should pass: %v", err)
+	}
+	// The body is still readable after validation.
+	if _, err := ch.ParseRequest(r); err != nil {
+		t.Fatalf("ParseRequest after ValidateRequest: %v", err)
+	}
+
+	tests := map[string]func(r *http.Request){
+		"wrong secret":      func(r *http.Request) { *r = *newGitHubRequest("pull_request", githubBody, "other") },
+		"missing signature": func(r *http.Request) { r.Header.Del("X-Hub-Signature-256") },
+		"malformed hex":     func(r *http.Request) { r.Header.Set("X-Hub-Signature-256", "sha256=zz") },
+		"sha1 signature":    func(r *http.Request) { r.Header.Set("X-Hub-Signature-256", "sha1=00") },
+		"missing event":     func(r *http.Request) { r.Header.Del("X-GitHub-Event") },
+		"form content type": func(r *http.Request) { r.Header.Set("Content-Type", "application/x-www-form-urlencoded") },
+	}
+	for name, mutate := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := newGitHubRequest("pull_request", githubBody, "s3cret")
+			mutate(r)
+			if err := ch.ValidateRequest(r); err == nil {
+				t.Fatal("expected a validation error, got nil")
+			}
+		})
+	}
+}
+
+func TestGitHubChannel_ParseRequest(t *testing.T) {
+	ch := NewGitHubChannel("gh", "s3cret", []string{"pull_request", "workflow_run"})
+	ev, err := ch.ParseRequest(newGitHubRequest("pull_request", githubBody, "s3cret"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := map[string]string{
+		"github.event":      "pull_request",
+		"github.delivery":   "72d3162e-cc78-11e3-81ab-4c9367dc0958",
+		"github.action":     "opened",
+		"github.repository": "octo/repo",
+	}
+	for k, v := range want {
+		if ev.Metadata[k] != v {
+			t.Errorf("metadata[%q] = %q, want %q", k, ev.Metadata[k], v)
+		}
+	}
+	if string(ev.RawBody) != githubBody {
+		t.Errorf("raw_body = %s, want the payload", ev.RawBody)
+	}
+}
+
+func TestGitHubChannel_ParseRequest_Replies(t *testing.T) {
+	ch := NewGitHubChannel("gh", "s3cret", []string{"workflow_run"})
+
+	for _, event := range []string{"ping", "push"} {
+		_, err := ch.ParseRequest(newGitHubRequest(event, `{"zen":"Keep it logically awesome."}`, "s3cret"))
+		var reply *types.Reply
+		if !errors.As(err, &reply) {
+			t.Fatalf("%s: error = %v, want a *types.Reply", event, err)
+		}
+		if reply.Status != http.StatusOK {
+			t.Errorf("%s: reply status = %d, want 200", event, reply.Status)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package channel
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/youmna-rabie/claude-pod/internal/types"
+)
+
+// hmacHashes maps the configurable algorithm names to hash functions.
+var hmacHashes = map[string]func() hash.Hash{
+	"sha1":   sha1.New,
+	"sha256": sha256.New,
+	"sha512": sha512.New,
+}
+
+// HMACOptions describe how an HMACChannel's senders sign requests. They
+// mirror config.HMACConfig, whose defaults and validation they rely on.
+type HMACOptions struct {
+	Algorithm       string // sha1, sha256 or sha512
+	Header          string // header carrying the signature
+	Prefix          string // stripped from the header value before decoding
+	Encoding        string // hex or base64
+	Secrets         []string
+	TimestampHeader string        // if set, the signature covers "<timestamp>.<body>"
+	Tolerance       time.Duration // how far the timestamp may be from now
+}
+
+// HMACChannel accepts JSON webhooks signed with an HMAC of the body, for
+// senders that each put the signature in a different header and encoding.
+type HMACChannel struct {
+	name string
+	opts HMACOptions
+	hash func() hash.Hash
+	now  func() time.Time
+}
+
+// NewHMACChannel creates an HMACChannel verifying signatures as opts
+// describe. A signature made with any of opts.Secrets is accepted.
+func NewHMACChannel(name string, opts HMACOptions) *HMACChannel {
+	return &HMACChannel{name: name, opts: opts, hash: hmacHashes[opts.Algorithm], now: time.Now}
+}
+
+func (h *HMACChannel) Name() string {
+	return h.name
+}
+
+func (h *HMACChannel) ValidateRequest(r *http.Request) error {
+	if r.Method != http.MethodPost {
+		return fmt.Errorf("method %s not allowed, expected POST", r.Method)
+	}
+	if mt := mediaType(r); mt != "application/json" {
+		return fmt.Errorf("unsupported Content-Type %q, expected application/json", r.Header.Get("Content-Type"))
+	}
+
+	body, err := readBody(r)
+	if err != nil {
+		return err
+	}
+	message := body
+	if h.opts.TimestampHeader != "" {
+		ts := r.Header.Get(h.opts.TimestampHeader)
+		secs, err := strconv.ParseInt(ts, 10, 64)
+		if err != nil {
+			return fmt.Errorf("missing or invalid %s header", h.opts.TimestampHeader)
+		}
+		if age := h.now().Sub(time.Unix(secs, 0)); age > h.opts.Tolerance || age < -h.opts.Tolerance {
+			return fmt.Errorf("%s is more than %s from now", h.opts.TimestampHeader, h.opts.Tolerance)
+		}
+		message = append([]byte(ts+"."), body...)
+	}
+
+	encoded, ok := strings.CutPrefix(r.Header.Get(h.opts.Header), h.opts.Prefix)
+	if !ok || encoded == "" {
+		return fmt.Errorf("missing %s header", h.opts.Header)
+	}
+	sig, err := h.decode(encoded)
+	if err != nil {
+		return fmt.Errorf("invalid %s signature", h.opts.Header)
+	}
+	for _, secret := range h.opts.Secrets {
+		if validHMAC(h.hash, secret, message, sig) {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid %s signature", h.opts.Header)
+}
+
+// decode turns a signature from the header back into bytes. Base64 is
+// accepted in both the standard and URL-safe alphabets, padded or not.
+func (h *HMACChannel) decode(s string) ([]byte, error) {
+	if h.opts.Encoding == "hex" {
+		return hex.DecodeString(s)
+	}
+	s = strings.TrimRight(s, "=")
+	if strings.ContainsAny(s, "-_") {
+		return base64.RawURLEncoding.DecodeString(s)
+	}
+	return base64.RawStdEncoding.DecodeString(s)
+}
+
+func (h *HMACChannel) ParseRequest(r *http.Request) (*types.Event, error) {
+	body, err := readBody(r)
+	if err != nil {
+		return nil, err
+	}
+	if !json.Valid(body) {
+		return nil, fmt.Errorf("request body is not valid JSON")
+	}
+
+	return &types.Event{
+		ID:        uuid.New(),
+		ChannelID: h.name,
+		RawBody:   json.RawMessage(body),
+		Headers:   extractHeaders(r),
+		Timestamp: time.Now(),
+		Status:    types.EventStatusReceived,
+	}, nil
+}
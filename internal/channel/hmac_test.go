@@ -0,0 +1,156 @@
+package channel
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"hash"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+const hmacBody = `{"deploy":"api","status":"done"}`
+
+func sign(h func() hash.Hash, secret, message string) []byte {
+	mac := hmac.New(h, []byte(secret))
+	mac.Write([]byte(message))
+	return mac.Sum(nil)
+}
+
+func newHMACRequest(header, value string) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(hmacBody))
+	r.Header.Set("Content-Type", "application/json")
+	r.Header.Set(header, value)
+	return r
+}
+
+func TestHMACChannel_Schemes(t *testing.T) {
+	tests := []struct {
+		name  string
+		opts  HMACOptions
+		value string
+	}{
+		{
+			"sha256 hex with prefix",
+			HMACOptions{Algorithm: "sha256", Header: "X-Signature", Prefix: "sha256=", Encoding: "hex"},
+			"sha256=" + hex.EncodeToString(sign(sha256.New, "s3cret", hmacBody)),
+		},
+		{
+			"sha1 hex",
+			HMACOptions{Algorithm: "sha1", Header: "X-Hook-Sig", Encoding: "hex"},
+			hex.EncodeToString(sign(sha1.New, "s3cret", hmacBody)),
+		},
+		{
+			"sha512 base64",
+			HMACOptions{Algorithm: "sha512", Header: "X-Signature", Encoding: "base64"},
+			base64.StdEncoding.EncodeToString(sign(sha512.New, "s3cret", hmacBody)),
+		},
+		{
+			"sha256 url-safe base64 without padding",
+			HMACOptions{Algorithm: "sha256", Header: "X-Signature", Encoding: "base64"},
+			base64.RawURLEncoding.EncodeToString(sign(sha256.New, "s3cret", hmacBody)),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.Secrets = []string{"s3cret"}
+			ch := NewHMACChannel("hooks", tt.opts)
+
+			r := newHMACRequest(tt.opts.Header, tt.value)
+			if err := ch.ValidateRequest(r); err != nil {
+				t.Fatalf("correctly signed request should pass: %v", err)
+			}
+			if _, err := ch.ParseRequest(r); err != nil {
+				t.Fatalf("ParseRequest after ValidateRequest: %v", err)
+			}
+
+			wrong := NewHMACChannel("hooks", HMACOptions{
+				Algorithm: tt.opts.Algorithm, Header: tt.opts.Header, Prefix: tt.opts.Prefix,
+				Encoding: tt.opts.Encoding, Secrets: []string{"other"},
+			})
+			if err := wrong.ValidateRequest(newHMACRequest(tt.opts.Header, tt.value)); err == nil {
+				t.Fatal("request signed with another secret should fail")
+			}
+		})
+	}
+}
+
+func TestHMACChannel_RejectsBadSignatures(t *testing.T) {
+	ch := NewHMACChannel("hooks", HMACOptions{
+		Algorithm: "sha256", Header: "X-Signature", Prefix: "sha256=", Encoding: "hex", Secrets: []string{"s3cret"},
+	})
+	valid := hex.EncodeToString(sign(sha256.New, "s3cret", hmacBody))
+
+	tests := map[string]*http.Request{
+		"missing header": newHMACRequest("X-Other", "sha256="+valid),
+		"missing prefix": newHMACRequest("X-Signature", valid),
+		"empty value":    newHMACRequest("X-Signature", "sha256="),
+		"not hex":        newHMACRequest("X-Signature", "sha256=not-hex"),
+		"tampered":       newHMACRequest("X-Signature", "sha256="+strings.Repeat("0", len(valid))),
+	}
+	for name, r := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := ch.ValidateRequest(r); err == nil {
+				t.Fatal("expected a validation error, got nil")
+			}
+		})
+	}
+}
+
+func TestHMACChannel_SecretRotation(t *testing.T) {
+	ch := NewHMACChannel("hooks", HMACOptions{
+		Algorithm: "sha256", Header: "X-Signature", Encoding: "hex", Secrets: []string{"new", "old"},
+	})
+	for _, secret := range []string{"new", "old"} {
+		r := newHMACRequest("X-Signature", hex.EncodeToString(sign(sha256.New, secret, hmacBody)))
+		if err := ch.ValidateRequest(r); err != nil {
+			t.Errorf("request signed with %q should pass: %v", secret, err)
+		}
+	}
+}
+
+func TestHMACChannel_Timestamp(t *testing.T) {
+	ch := NewHMACChannel("hooks", HMACOptions{
+		Algorithm: "sha256", Header: "X-Signature", Encoding: "hex", Secrets: []string{"s3cret"},
+		TimestampHeader: "X-Timestamp", Tolerance: time.Minute,
+	})
+	now := time.Now()
+	ch.now = func() time.Time { return now }
+
+	request := func(at time.Time, signed string) *http.Request {
+		ts := strconv.FormatInt(at.Unix(), 10)
+		r := newHMACRequest("X-Signature", hex.EncodeToString(sign(sha256.New, "s3cret", signed)))
+		r.Header.Set("X-Timestamp", ts)
+		return r
+	}
+	stamp := func(at time.Time) string { return strconv.FormatInt(at.Unix(), 10) + "." + hmacBody }
+
+	if err := ch.ValidateRequest(request(now, stamp(now))); err != nil {
+		t.Fatalf("fresh, correctly signed request should pass: %v", err)
+	}
+
+	tests := map[string]*http.Request{
+		"stale":              request(now.Add(-2*time.Minute), stamp(now.Add(-2*time.Minute))),
+		"future":             request(now.Add(2*time.Minute), stamp(now.Add(2*time.Minute))),
+		"timestamp unsigned": request(now, hmacBody),
+		"timestamp swapped":  request(now, stamp(now.Add(-30*time.Second))),
+	}
+	missing := request(now, stamp(now))
+	missing.Header.Del("X-Timestamp")
+	tests["missing timestamp"] = missing
+
+	for name, r := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := ch.ValidateRequest(r); err == nil {
+				t.Fatal("expected a validation error, got nil")
+			}
+		})
+	}
+}
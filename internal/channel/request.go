@@ -0,0 +1,50 @@
+package channel
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/subtle"
+	"fmt"
+	"hash"
+	"io"
+	"mime"
+	"net/http"
+)
+
+// readBody reads the request body, up to maxBodySize, and puts it back so a
+// later stage can read it again.
+func readBody(r *http.Request) ([]byte, error) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize+1))
+	if err != nil {
+		return nil, fmt.Errorf("reading request body: %w", err)
+	}
+	if len(body) > maxBodySize {
+		return nil, fmt.Errorf("request body exceeds 1MB limit")
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+// mediaType returns the request's Content-Type without parameters such as
+// charset, or "" if it is missing or malformed.
+func mediaType(r *http.Request) string {
+	mt, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return ""
+	}
+	return mt
+}
+
+// validHMAC reports whether sig is the HMAC of message under secret, using
+// the hash function h.
+func validHMAC(h func() hash.Hash, secret string, message, sig []byte) bool {
+	mac := hmac.New(h, []byte(secret))
+	mac.Write(message)
+	return hmac.Equal(mac.Sum(nil), sig)
+}
+
+// equalSecret compares a received credential with the expected one in
+// constant time.
+func equalSecret(got, want string) bool {
+	return subtle.ConstantTimeCompare([]byte(got), []byte(want)) == 1
+}
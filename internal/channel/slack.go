@@ -0,0 +1,242 @@
+package channel
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/youmna-rabie/claude-pod/internal/types"
+)
+
+const (
+	// slackTolerance is how far X-Slack-Request-Timestamp may be from now,
+	// bounding how long a captured request can be replayed.
+	slackTolerance = 5 * time.Minute
+
+	// slackSeenTTL is how long event IDs are remembered for spotting
+	// retries. Slack retries up to three times, the last after 5 minutes.
+	slackSeenTTL = time.Hour
+)
+
+// SlackChannel accepts Slack Events API callbacks and slash commands signed
+// with X-Slack-Signature. It answers url_verification challenges itself and
+// drops retries of events it has already accepted, unless the gateway failed
+// to process them.
+type SlackChannel struct {
+	name   string
+	secret string
+	now    func() time.Time
+
+	mu   sync.Mutex
+	seen map[string]time.Time // event_id -> when first accepted
+}
+
+// NewSlackChannel creates a SlackChannel verifying signatures with the app's
+// signing secret.
+func NewSlackChannel(name, secret string) *SlackChannel {
+	return &SlackChannel{
+		name:   name,
+		secret: secret,
+		now:    time.Now,
+		seen:   make(map[string]time.Time),
+	}
+}
+
+func (s *SlackChannel) Name() string {
+	return s.name
+}
+
+func (s *SlackChannel) ValidateRequest(r *http.Request) error {
+	if r.Method != http.MethodPost {
+		return fmt.Errorf("method %s not allowed, expected POST", r.Method)
+	}
+	switch mt := mediaType(r); mt {
+	case "application/json", "application/x-www-form-urlencoded":
+	default:
+		return fmt.Errorf("unsupported Content-Type %q, expected application/json or application/x-www-form-urlencoded", r.Header.Get("Content-Type"))
+	}
+
+	ts := r.Header.Get("X-Slack-Request-Timestamp")
+	secs, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return fmt.Errorf("missing or invalid X-Slack-Request-Timestamp header")
+	}
+	if age := s.now().Sub(time.Unix(secs, 0)); age > slackTolerance || age < -slackTolerance {
+		return fmt.Errorf("X-Slack-Request-Timestamp is more than %s from now", slackTolerance)
+	}
+
+	body, err := readBody(r)
+	if err != nil {
+		return err
+	}
+	hexSig, ok := strings.CutPrefix(r.Header.Get("X-Slack-Signature"), "v0=")
+	if !ok {
+		return fmt.Errorf("missing X-Slack-Signature header")
+	}
+	sig, err := hex.DecodeString(hexSig)
+	message := append([]byte("v0:"+ts+":"), body...)
+	if err != nil || !validHMAC(sha256.New, s.secret, message, sig) {
+		return fmt.Errorf("invalid X-Slack-Signature signature")
+	}
+	return nil
+}
+
+// ParseRequest turns an event callback or slash command into an event,
+// recording what it is, who sent it and where in the event's metadata.
+// url_verification challenges and retries of events already accepted are
+// answered with a *types.Reply.
+func (s *SlackChannel) ParseRequest(r *http.Request) (*types.Event, error) {
+	body, err := readBody(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var md map[string]string
+	if mediaType(r) == "application/x-www-form-urlencoded" {
+		body, md, err = parseSlashCommand(body)
+	} else {
+		md, err = s.parseCallback(body, r.Header.Get("X-Slack-Retry-Num"))
+	}
+	if err != nil {
+		return nil, err
+	}
+	if n := r.Header.Get("X-Slack-Retry-Num"); n != "" {
+		md["slack.retry_num"] = n
+		md["slack.retry_reason"] = r.Header.Get("X-Slack-Retry-Reason")
+	}
+
+	return &types.Event{
+		ID:        uuid.New(),
+		ChannelID: s.name,
+		RawBody:   json.RawMessage(body),
+		Headers:   extractHeaders(r),
+		Metadata:  md,
+		Timestamp: time.Now(),
+		Status:    types.EventStatusReceived,
+	}, nil
+}
+
+// parseCallback reads an Events API payload. retryNum is the
+// X-Slack-Retry-Num header, set when Slack is resending an event.
+func (s *SlackChannel) parseCallback(body []byte, retryNum string) (map[string]string, error) {
+	var payload struct {
+		Type      string `json:"type"`
+		Challenge string `json:"challenge"`
+		EventID   string `json:"event_id"`
+		TeamID    string `json:"team_id"`
+		Event     struct {
+			Type    string `json:"type"`
+			User    string `json:"user"`
+			Channel string `json:"channel"`
+		} `json:"event"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("request body is not valid JSON: %w", err)
+	}
+
+	switch payload.Type {
+	case "url_verification":
+		return nil, &types.Reply{Status: http.StatusOK, Body: map[string]string{"challenge": payload.Challenge}}
+	case "event_callback":
+		if payload.EventID == "" {
+			return nil, fmt.Errorf("event_callback is missing event_id")
+		}
+		if s.markSeen(payload.EventID) && retryNum != "" {
+			return nil, &types.Reply{Status: http.StatusOK, Body: map[string]string{
+				"status":   "duplicate",
+				"event_id": payload.EventID,
+			}}
+		}
+	case "":
+		return nil, fmt.Errorf("payload is missing type")
+	}
+
+	md := map[string]string{"slack.type": payload.Type}
+	for key, value := range map[string]string{
+		"slack.event_id":   payload.EventID,
+		"slack.team_id":    payload.TeamID,
+		"slack.event_type": payload.Event.Type,
+		"slack.user":       payload.Event.User,
+		"slack.channel":    payload.Event.Channel,
+	} {
+		if value != "" {
+			md[key] = value
+		}
+	}
+	return md, nil
+}
+
+// markSeen records eventID and reports whether it had already been seen.
+// Expired IDs are swept as new ones arrive.
+func (s *SlackChannel) markSeen(eventID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	for id, at := range s.seen {
+		if now.Sub(at) > slackSeenTTL {
+			delete(s.seen, id)
+		}
+	}
+	if _, ok := s.seen[eventID]; ok {
+		return true
+	}
+	s.seen[eventID] = now
+	return false
+}
+
+// Forget drops evt's event ID from the seen set, so that Slack's retry of an
+// event the gateway failed to store or forward is accepted.
+func (s *SlackChannel) Forget(evt *types.Event) {
+	id := evt.Metadata["slack.event_id"]
+	if id == "" {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.seen, id)
+}
+
+// parseSlashCommand converts a form-encoded slash command into a JSON object
+// of its fields, since event bodies are stored as JSON.
+func parseSlashCommand(body []byte) ([]byte, map[string]string, error) {
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		return nil, nil, fmt.Errorf("request body is not a valid form: %w", err)
+	}
+	if form.Get("command") == "" {
+		return nil, nil, fmt.Errorf("slash command is missing command")
+	}
+
+	fields := make(map[string]string, len(form))
+	for key := range form {
+		fields[key] = form.Get(key)
+	}
+	converted, err := json.Marshal(fields)
+	if err != nil {
+		return nil, nil, fmt.Errorf("encoding slash command: %w", err)
+	}
+
+	md := map[string]string{
+		"slack.type":    "slash_command",
+		"slack.command": form.Get("command"),
+	}
+	for key, field := range map[string]string{
+		"slack.team_id": "team_id",
+		"slack.user":    "user_id",
+		"slack.channel": "channel_id",
+	} {
+		if v := form.Get(field); v != "" {
+			md[key] = v
+		}
+	}
+	return converted, md, nil
+}
@@ -0,0 +1,187 @@
+package channel
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/youmna-rabie/claude-pod/internal/types"
+)
+
+const slackCallback = `{"type":"event_callback","team_id":"T1","event_id":"Ev1","event":{"type":"app_mention","user":"U1","channel":"C1","text":"<@A1> status?"}}`
+
+func newSlackRequest(contentType, body, secret string, at time.Time) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	r.Header.Set("Content-Type", contentType)
+	ts := strconv.FormatInt(at.Unix(), 10)
+	r.Header.Set("X-Slack-Request-Timestamp", ts)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("v0:" + ts + ":" + body))
+	r.Header.Set("X-Slack-Signature", "v0="+hex.EncodeToString(mac.Sum(nil)))
+	return r
+}
+
+func TestSlackChannel_ValidateRequest(t *testing.T) {
+	ch := NewSlackChannel("slack", "s3cret")
+	now := time.Now()
+
+	r := newSlackRequest("application/json", slackCallback, "s3cret", now)
+	if err := ch.ValidateRequest(r); err != nil {
+		t.Fatalf("correctly signed request should pass: %v", err)
+	}
+	if err := ch.ValidateRequest(newSlackRequest("application/json", slackCallback, "s3cret", now.Add(-4*time.Minute))); err != nil {
+		t.Fatalf("request within the timestamp window should pass: %v", err)
+	}
+
+	tests := map[string]*http.Request{
+		"wrong secret": newSlackRequest("application/json", slackCallback, "other", now),
+		"stale":        newSlackRequest("application/json", slackCallback, "s3cret", now.Add(-6*time.Minute)),
+		"future":       newSlackRequest("application/json", slackCallback, "s3cret", now.Add(6*time.Minute)),
+		"text body":    newSlackRequest("text/plain", slackCallback, "s3cret", now),
+	}
+	missingSig := newSlackRequest("application/json", slackCallback, "s3cret", now)
+	missingSig.Header.Del("X-Slack-Signature")
+	tests["missing signature"] = missingSig
+	missingTS := newSlackRequest("application/json", slackCallback, "s3cret", now)
+	missingTS.Header.Del("X-Slack-Request-Timestamp")
+	tests["missing timestamp"] = missingTS
+
+	for name, r := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := ch.ValidateRequest(r); err == nil {
+				t.Fatal("expected a validation error, got nil")
+			}
+		})
+	}
+}
+
+func TestSlackChannel_URLVerification(t *testing.T) {
+	ch := NewSlackChannel("slack", "s3cret")
+	body := `{"type":"url_verification","token":"x","challenge":"3eZbrw1aBm2rZgRNFdxV2595E9CY3gmdALWMmHkvFXO7tYXAYM8P"}`
+
+	_, err := ch.ParseRequest(newSlackRequest("application/json", body, "s3cret", time.Now()))
+	var reply *types.Reply
+	if !errors.As(err, &reply) {
+		t.Fatalf("error = %v, want a *types.Reply", err)
+	}
+	if got := reply.Body.(map[string]string)["challenge"]; got != "3eZbrw1aBm2rZgRNFdxV2595E9CY3gmdALWMmHkvFXO7tYXAYM8P" {
+		t.Errorf("challenge = %q, want it echoed", got)
+	}
+}
+
+func TestSlackChannel_EventCallback(t *testing.T) {
+	ch := NewSlackChannel("slack", "s3cret")
+	ev, err := ch.ParseRequest(newSlackRequest("application/json", slackCallback, "s3cret", time.Now()))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := map[string]string{
+		"slack.type":       "event_callback",
+		"slack.event_id":   "Ev1",
+		"slack.team_id":    "T1",
+		"slack.event_type": "app_mention",
+		"slack.user":       "U1",
+		"slack.channel":    "C1",
+	}
+	for k, v := range want {
+		if ev.Metadata[k] != v {
+			t.Errorf("metadata[%q] = %q, want %q", k, ev.Metadata[k], v)
+		}
+	}
+}
+
+func TestSlackChannel_IgnoresProcessedRetries(t *testing.T) {
+	ch := NewSlackChannel("slack", "s3cret")
+	now := time.Now()
+	ch.now = func() time.Time { return now }
+
+	retry := func() *http.Request {
+		r := newSlackRequest("application/json", slackCallback, "s3cret", now)
+		r.Header.Set("X-Slack-Retry-Num", "1")
+		r.Header.Set("X-Slack-Retry-Reason", "http_timeout")
+		return r
+	}
+
+	// A retry of an event never seen, say because the first attempt was
+	// lost, is accepted.
+	ev, err := ch.ParseRequest(retry())
+	if err != nil {
+		t.Fatalf("first delivery: %v", err)
+	}
+	if ev.Metadata["slack.retry_num"] != "1" || ev.Metadata["slack.retry_reason"] != "http_timeout" {
+		t.Errorf("metadata = %v, want the retry recorded", ev.Metadata)
+	}
+
+	_, err = ch.ParseRequest(retry())
+	var reply *types.Reply
+	if !errors.As(err, &reply) || reply.Status != http.StatusOK {
+		t.Fatalf("retry of a processed event: error = %v, want a 200 *types.Reply", err)
+	}
+
+	// Once the ID has expired, the event is accepted again.
+	now = now.Add(slackSeenTTL + time.Minute)
+	if _, err := ch.ParseRequest(retry()); err != nil {
+		t.Fatalf("retry after the seen window: %v", err)
+	}
+}
+
+func TestSlackChannel_SlashCommand(t *testing.T) {
+	ch := NewSlackChannel("slack", "s3cret")
+	form := "command=%2Fagent&text=restart+api&user_id=U1&channel_id=C1&team_id=T1&response_url=https%3A%2F%2Fhooks.slack.com%2Fcommands%2F1"
+
+	r := newSlackRequest("application/x-www-form-urlencoded", form, "s3cret", time.Now())
+	if err := ch.ValidateRequest(r); err != nil {
+		t.Fatalf("ValidateRequest: %v", err)
+	}
+	ev, err := ch.ParseRequest(r)
+	if err != nil {
+		t.Fatalf("ParseRequest: %v", err)
+	}
+
+	var body map[string]string
+	if err := json.Unmarshal(ev.RawBody, &body); err != nil {
+		t.Fatalf("raw_body is not a JSON object: %v", err)
+	}
+	if body["command"] != "/agent" || body["text"] != "restart api" {
+		t.Errorf("raw_body = %v, want the decoded form fields", body)
+	}
+	want := map[string]string{
+		"slack.type":    "slash_command",
+		"slack.command": "/agent",
+		"slack.user":    "U1",
+		"slack.channel": "C1",
+		"slack.team_id": "T1",
+	}
+	for k, v := range want {
+		if ev.Metadata[k] != v {
+			t.Errorf("metadata[%q] = %q, want %q", k, ev.Metadata[k], v)
+		}
+	}
+}
+
+func TestSlackChannel_ForgetAcceptsRetry(t *testing.T) {
+	var _ types.DedupChannel = (*SlackChannel)(nil)
+
+	ch := NewSlackChannel("slack", "s3cret")
+	ev, err := ch.ParseRequest(newSlackRequest("application/json", slackCallback, "s3cret", time.Now()))
+	if err != nil {
+		t.Fatalf("first delivery: %v", err)
+	}
+
+	// The gateway failed to process the first delivery, so the retry counts.
+	ch.Forget(ev)
+	r := newSlackRequest("application/json", slackCallback, "s3cret", time.Now())
+	r.Header.Set("X-Slack-Retry-Num", "1")
+	if _, err := ch.ParseRequest(r); err != nil {
+		t.Fatalf("retry after Forget: %v", err)
+	}
+}
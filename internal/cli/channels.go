@@ -1,36 +1,106 @@
 package cli
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"slices"
 
 	"github.com/spf13/cobra"
 	"github.com/youmna-rabie/claude-pod/internal/config"
 )
 
 func init() {
+	registerOutputFlags(listChannelsCmd)
+	registerRemoteFlag(listChannelsCmd)
 	rootCmd.AddCommand(listChannelsCmd)
 }
 
 var listChannelsCmd = &cobra.Command{
 	Use:   "list-channels",
 	Short: "Print configured channels",
-	RunE:  listChannels,
+	Long: "Print the channels in the local config, or with --remote, --server or\n" +
+		"--watch, the channels registered on a running gateway.",
+	RunE: listChannels,
+}
+
+// channelInfo is the local listing of a configured channel. The auth
+// secret is deliberately left out.
+type channelInfo struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
 }
 
 func listChannels(cmd *cobra.Command, args []string) error {
+	if err := validateOutput(); err != nil {
+		return err
+	}
+	if !useRemote() {
+		return listLocalChannels()
+	}
+	if watchMode {
+		ctx, stop := interruptContext()
+		defer stop()
+		return watch(ctx, func() error {
+			if outputFormat == "" || outputFormat == "table" {
+				clearScreen()
+			}
+			return listRemoteChannels()
+		})
+	}
+	return listRemoteChannels()
+}
+
+func listLocalChannels() error {
 	cfg, err := config.Load(configPath)
 	if err != nil {
 		return fmt.Errorf("loading config: %w", err)
 	}
 
-	if len(cfg.Channels) == 0 {
-		fmt.Println("No channels configured.")
-		return nil
+	channels := make([]channelInfo, 0, len(cfg.Channels))
+	for _, ch := range cfg.Channels {
+		channels = append(channels, channelInfo{Name: ch.Name, Type: ch.Type})
+	}
+	data, err := json.Marshal(map[string]any{"channels": channels, "count": len(channels)})
+	if err != nil {
+		return err
 	}
 
-	fmt.Printf("%-20s %-15s\n", "NAME", "TYPE")
-	for _, ch := range cfg.Channels {
-		fmt.Printf("%-20s %-15s\n", ch.Name, ch.Type)
+	return printOutput(data, func() error {
+		if len(channels) == 0 {
+			fmt.Println("No channels configured.")
+			return nil
+		}
+		fmt.Printf("%-20s %-15s\n", "NAME", "TYPE")
+		for _, ch := range channels {
+			fmt.Printf("%-20s %-15s\n", ch.Name, ch.Type)
+		}
+		return nil
+	})
+}
+
+func listRemoteChannels() error {
+	var raw json.RawMessage
+	if err := callAdmin(http.MethodGet, "/admin/channels", nil, &raw); err != nil {
+		return err
 	}
-	return nil
+	var resp struct {
+		Channels []string `json:"channels"`
+	}
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return fmt.Errorf("decoding response: %w", err)
+	}
+
+	return printOutput(raw, func() error {
+		if len(resp.Channels) == 0 {
+			fmt.Println("No channels registered.")
+			return nil
+		}
+		slices.Sort(resp.Channels)
+		fmt.Println("NAME")
+		for _, name := range resp.Channels {
+			fmt.Println(name)
+		}
+		return nil
+	})
 }
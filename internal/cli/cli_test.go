@@ -1,11 +1,24 @@
 package cli
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/spf13/cobra"
+	"github.com/youmna-rabie/claude-pod/internal/agent"
+	"github.com/youmna-rabie/claude-pod/internal/channel"
 	"github.com/youmna-rabie/claude-pod/internal/config"
+	"github.com/youmna-rabie/claude-pod/internal/event"
 	"github.com/youmna-rabie/claude-pod/internal/types"
 )
 
@@ -24,12 +37,33 @@ func TestBuildChannels(t *testing.T) {
 		{Name: "grafana-alerts", Type: "grafana", Auth: "secret"},
 		{Name: "generic", Type: "dummy"},
 		{Name: "unknown-type", Type: "whatever"},
+		{Name: "am", Type: "alertmanager", Auth: "secret"},
+		{Name: "gh", Type: "github", Secret: "secret"},
+		{Name: "slack", Type: "slack", Secret: "secret"},
+		{Name: "hooks", Type: "hmac", HMAC: config.HMACConfig{Algorithm: "sha256", Secrets: []string{"secret"}}},
+		{Name: "ce", Type: "cloudevents"},
 	}
 
 	channels := buildChannels(cfgs)
 
-	if len(channels) != 3 {
-		t.Fatalf("expected 3 channels, got %d", len(channels))
+	if len(channels) != 8 {
+		t.Fatalf("expected 8 channels, got %d", len(channels))
+	}
+
+	if _, ok := channels["am"].(*channel.AlertmanagerChannel); !ok {
+		t.Errorf("am channel is %T, want *channel.AlertmanagerChannel", channels["am"])
+	}
+	if _, ok := channels["gh"].(*channel.GitHubChannel); !ok {
+		t.Errorf("gh channel is %T, want *channel.GitHubChannel", channels["gh"])
+	}
+	if _, ok := channels["slack"].(*channel.SlackChannel); !ok {
+		t.Errorf("slack channel is %T, want *channel.SlackChannel", channels["slack"])
+	}
+	if _, ok := channels["hooks"].(*channel.HMACChannel); !ok {
+		t.Errorf("hooks channel is %T, want *channel.HMACChannel", channels["hooks"])
+	}
+	if _, ok := channels["ce"].(types.BatchChannel); !ok {
+		t.Errorf("ce channel is %T, want a types.BatchChannel", channels["ce"])
 	}
 
 	// Grafana type should produce GrafanaChannel.
@@ -61,6 +95,82 @@ func TestBuildChannelsEmpty(t *testing.T) {
 	}
 }
 
+func TestNewStore(t *testing.T) {
+	logger := newLogger(config.LoggingConfig{Level: "error", Format: "json"})
+	mem, err := newStore(config.StoreConfig{Type: "memory", Capacity: 10}, logger)
+	if err != nil {
+		t.Fatalf("memory store: %v", err)
+	}
+	if _, ok := mem.(*event.MemoryStore); !ok {
+		t.Errorf("memory type should produce MemoryStore, got %T", mem)
+	}
+
+	sqlite, err := newStore(config.StoreConfig{Type: "sqlite", Capacity: 10, Path: filepath.Join(t.TempDir(), "events.db")}, logger)
+	if err != nil {
+		t.Fatalf("sqlite store: %v", err)
+	}
+	defer sqlite.(*event.SQLiteStore).Close()
+
+	file, err := newStore(config.StoreConfig{Type: "file", Capacity: 10, Path: t.TempDir()}, logger)
+	if err != nil {
+		t.Fatalf("file store: %v", err)
+	}
+	defer file.(*event.FileStore).Close()
+
+	if _, err := newStore(config.StoreConfig{Type: "redis", Capacity: 10}, logger); err == nil {
+		t.Error("unsupported store type should return an error")
+	}
+}
+
+func TestNewAgentClient(t *testing.T) {
+	logger := newLogger(config.LoggingConfig{Level: "error", Format: "json"})
+
+	client, breaker := newAgentClient(config.AgentConfig{Type: "stub"}, logger)
+	if client != agent.Client(breaker) {
+		t.Error("client chain should be fronted by the circuit breaker")
+	}
+	resp, err := client.Forward(context.Background(), types.EventEnvelope{})
+	if err != nil || resp.Status != "ok" {
+		t.Errorf("stub chain Forward = %+v, %v; want ok", resp, err)
+	}
+}
+
+func TestNewAgentClientHTTPRetries(t *testing.T) {
+	calls := 0
+	agentSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"status":"ok"}`))
+	}))
+	defer agentSrv.Close()
+
+	logger := newLogger(config.LoggingConfig{Level: "error", Format: "json"})
+	client, breaker := newAgentClient(config.AgentConfig{
+		Type:    "http",
+		URL:     agentSrv.URL,
+		Timeout: time.Second,
+		Retry:   config.RetryConfig{MaxAttempts: 3, BaseBackoff: time.Millisecond, MaxBackoff: time.Millisecond},
+		Breaker: config.BreakerConfig{FailureThreshold: 5, Cooldown: time.Minute},
+	}, logger)
+
+	resp, err := client.Forward(context.Background(), types.EventEnvelope{})
+	if err != nil {
+		t.Fatalf("Forward: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("agent calls = %d, want 3", calls)
+	}
+	if resp.Attempts != 3 {
+		t.Errorf("attempts = %d, want 3", resp.Attempts)
+	}
+	if breaker.State() != agent.BreakerClosed {
+		t.Errorf("breaker state = %s, want closed", breaker.State())
+	}
+}
+
 func TestNewLogger(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -142,10 +252,31 @@ server:
 }
 
 func TestListEventsCommand(t *testing.T) {
-	eventsLimit = 10
-	err := listEvents(nil, nil)
-	if err != nil {
-		t.Fatalf("listEvents returned error: %v", err)
+	var query string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/admin/events" {
+			t.Errorf("path = %s, want /admin/events", r.URL.Path)
+		}
+		query = r.URL.RawQuery
+		w.Write([]byte(`{"events":[{"id":"6f1c5d1e-0000-4000-8000-000000000001","channel_id":"grafana","status":"failed"}],"count":1}`))
+	}))
+	defer srv.Close()
+	useServer(t, srv.URL)
+
+	old := eventsFilters
+	eventsFilters = eventFilters{channel: "grafana", status: "failed", limit: 10}
+	defer func() { eventsFilters = old }()
+
+	out := captureStdout(t, func() {
+		if err := listEvents(nil, nil); err != nil {
+			t.Fatalf("listEvents returned error: %v", err)
+		}
+	})
+	if query != "channel=grafana&limit=10&status=failed" {
+		t.Errorf("query = %q", query)
+	}
+	if !strings.Contains(out, "6f1c5d1e-0000-4000-8000-000000000001") || !strings.Contains(out, "grafana") {
+		t.Errorf("table output missing event:\n%s", out)
 	}
 }
 
@@ -181,3 +312,391 @@ func TestBuildChannelsInterfaceConformance(t *testing.T) {
 		}
 	}
 }
+
+// useServer points admin API commands at addr for the duration of the test.
+func useServer(t *testing.T, addr string) {
+	t.Helper()
+	old := serverAddr
+	serverAddr = addr
+	t.Cleanup(func() { serverAddr = old })
+}
+
+func TestAdminURL(t *testing.T) {
+	useServer(t, "gateway.internal:9000")
+	if got, _ := adminURL(); got != "http://gateway.internal:9000" {
+		t.Errorf("adminURL with --server = %q", got)
+	}
+
+	useServer(t, "https://gateway.example.com/")
+	if got, _ := adminURL(); got != "https://gateway.example.com" {
+		t.Errorf("adminURL with scheme = %q", got)
+	}
+
+	useServer(t, "")
+	old := configPath
+	configPath = writeTestConfig(t, "server:\n  host: 0.0.0.0\n  port: 9090\n")
+	defer func() { configPath = old }()
+	if got, _ := adminURL(); got != "http://127.0.0.1:9090" {
+		t.Errorf("adminURL from config = %q, want http://127.0.0.1:9090", got)
+	}
+}
+
+func TestAdminURLUsesAdminListener(t *testing.T) {
+	useServer(t, "")
+	old := configPath
+	configPath = writeTestConfig(t, "server:\n  port: 9090\nadmin:\n  host: 10.0.0.5\n  port: 9091\n")
+	defer func() { configPath = old }()
+	if got, _ := adminURL(); got != "http://10.0.0.5:9091" {
+		t.Errorf("adminURL with admin listener = %q, want http://10.0.0.5:9091", got)
+	}
+}
+
+func TestAdminCallsSendToken(t *testing.T) {
+	var auth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		w.Write([]byte(`{"event_id":"abc","status":"forwarded","attempts":1}`))
+	}))
+	defer srv.Close()
+	useServer(t, srv.URL)
+
+	old := adminToken
+	adminToken = "s3cret"
+	defer func() { adminToken = old }()
+
+	if err := replayEvents(nil, []string{"abc"}); err != nil {
+		t.Fatalf("replayEvents: %v", err)
+	}
+	if auth != "Bearer s3cret" {
+		t.Errorf("Authorization = %q, want Bearer s3cret", auth)
+	}
+}
+
+func TestReplayCommandSingle(t *testing.T) {
+	var path string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.Method + " " + r.URL.Path
+		w.Write([]byte(`{"event_id":"abc","status":"forwarded","attempts":1}`))
+	}))
+	defer srv.Close()
+	useServer(t, srv.URL)
+
+	if err := replayEvents(nil, []string{"abc"}); err != nil {
+		t.Fatalf("replayEvents: %v", err)
+	}
+	if path != "POST /admin/events/abc/replay" {
+		t.Errorf("request = %q, want POST /admin/events/abc/replay", path)
+	}
+}
+
+func TestReplayCommandSingleFailure(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+		w.Write([]byte(`{"event_id":"abc","status":"failed","error":"agent returned status 500"}`))
+	}))
+	defer srv.Close()
+	useServer(t, srv.URL)
+
+	if err := replayEvents(nil, []string{"abc"}); err == nil {
+		t.Fatal("expected an error when the replay fails")
+	}
+}
+
+func TestReplayCommandBulkFollowsCursor(t *testing.T) {
+	var queries []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/admin/replay" {
+			t.Errorf("path = %s, want /admin/replay", r.URL.Path)
+		}
+		queries = append(queries, r.URL.RawQuery)
+		if r.URL.Query().Get("cursor") == "" {
+			w.Write([]byte(`{"results":[{"event_id":"a","status":"forwarded"}],"failed":0,"next_cursor":"c1"}`))
+			return
+		}
+		w.Write([]byte(`{"results":[{"event_id":"b","status":"forwarded"}],"failed":0}`))
+	}))
+	defer srv.Close()
+	useServer(t, srv.URL)
+
+	old := replayFilters
+	replayFilters = eventFilters{channel: "grafana", status: "failed", since: "1h", limit: 1}
+	defer func() { replayFilters = old }()
+
+	if err := replayEvents(nil, nil); err != nil {
+		t.Fatalf("replayEvents: %v", err)
+	}
+	want := []string{
+		"channel=grafana&limit=1&since=1h&status=failed",
+		"channel=grafana&cursor=c1&limit=1&since=1h&status=failed",
+	}
+	if len(queries) != len(want) {
+		t.Fatalf("made %d requests, want %d: %v", len(queries), len(want), queries)
+	}
+	for i := range want {
+		if queries[i] != want[i] {
+			t.Errorf("request %d query = %q, want %q", i, queries[i], want[i])
+		}
+	}
+}
+
+func TestReplayCommandServerError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":"at least one filter is required"}`))
+	}))
+	defer srv.Close()
+	useServer(t, srv.URL)
+
+	err := replayEvents(nil, nil)
+	var apiErr *adminError
+	if !errors.As(err, &apiErr) || apiErr.Message != "at least one filter is required" {
+		t.Errorf("error = %v, want admin error with server message", err)
+	}
+}
+
+func TestReplayTimeoutScalesWithLimit(t *testing.T) {
+	if got := replayTimeout(1); got != adminTimeout {
+		t.Errorf("replayTimeout(1) = %s, want %s", got, adminTimeout)
+	}
+	if got, want := replayTimeout(0), 50*replayEventTimeout; got != want {
+		t.Errorf("replayTimeout(0) = %s, want %s for the default limit", got, want)
+	}
+	if got, want := replayTimeout(200), 200*replayEventTimeout; got != want {
+		t.Errorf("replayTimeout(200) = %s, want %s", got, want)
+	}
+}
+
+// captureStdout returns what fn prints to stdout.
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	old := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = old }()
+
+	done := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(r)
+		done <- string(data)
+	}()
+	fn()
+	w.Close()
+	return <-done
+}
+
+// useOutput sets the list commands' output flags for the duration of the test.
+func useOutput(t *testing.T, format string, remote bool) {
+	t.Helper()
+	oldFormat, oldRemote, oldWatch := outputFormat, remoteMode, watchMode
+	outputFormat, remoteMode, watchMode = format, remote, false
+	t.Cleanup(func() { outputFormat, remoteMode, watchMode = oldFormat, oldRemote, oldWatch })
+}
+
+func TestListChannelsOutputFormats(t *testing.T) {
+	old := configPath
+	configPath = writeTestConfig(t, `
+server:
+  port: 9090
+channels:
+  - name: alerts
+    type: grafana
+    auth: s3cret
+`)
+	defer func() { configPath = old }()
+
+	useOutput(t, "json", false)
+	out := captureStdout(t, func() {
+		if err := listChannels(nil, nil); err != nil {
+			t.Fatalf("listChannels: %v", err)
+		}
+	})
+	var resp struct {
+		Channels []channelInfo `json:"channels"`
+		Count    int           `json:"count"`
+	}
+	if err := json.Unmarshal([]byte(out), &resp); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, out)
+	}
+	if resp.Count != 1 || resp.Channels[0] != (channelInfo{Name: "alerts", Type: "grafana"}) {
+		t.Errorf("json output = %+v", resp)
+	}
+	if strings.Contains(out, "s3cret") {
+		t.Error("output leaks the channel auth secret")
+	}
+
+	useOutput(t, "yaml", false)
+	out = captureStdout(t, func() {
+		if err := listChannels(nil, nil); err != nil {
+			t.Fatalf("listChannels: %v", err)
+		}
+	})
+	if !strings.Contains(out, "name: alerts") || !strings.Contains(out, "count: 1") {
+		t.Errorf("yaml output:\n%s", out)
+	}
+}
+
+func TestListChannelsRemote(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/admin/channels" {
+			t.Errorf("path = %s, want /admin/channels", r.URL.Path)
+		}
+		w.Write([]byte(`{"channels":["zeta","alpha"],"count":2}`))
+	}))
+	defer srv.Close()
+	useServer(t, srv.URL)
+	useOutput(t, "table", false)
+
+	out := captureStdout(t, func() {
+		if err := listChannels(nil, nil); err != nil {
+			t.Fatalf("listChannels: %v", err)
+		}
+	})
+	if out != "NAME\nalpha\nzeta\n" {
+		t.Errorf("output = %q, want sorted channel names", out)
+	}
+}
+
+func TestListSkillsRemote(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"skills":[{"name":"triage","description":"Triage alerts","path":"/skills/triage"}],"count":1}`))
+	}))
+	defer srv.Close()
+	useServer(t, srv.URL)
+	useOutput(t, "json", true)
+
+	out := captureStdout(t, func() {
+		if err := listSkills(nil, nil); err != nil {
+			t.Fatalf("listSkills: %v", err)
+		}
+	})
+	if !strings.Contains(out, `"name": "triage"`) {
+		t.Errorf("json output:\n%s", out)
+	}
+}
+
+func TestListCommandsRejectUnknownOutput(t *testing.T) {
+	useOutput(t, "xml", false)
+	for name, run := range map[string]func(*cobra.Command, []string) error{
+		"list-events":   listEvents,
+		"list-channels": listChannels,
+		"list-skills":   listSkills,
+	} {
+		if err := run(nil, nil); err == nil || !strings.Contains(err.Error(), "unsupported output format") {
+			t.Errorf("%s error = %v, want unsupported output format", name, err)
+		}
+	}
+}
+
+func TestEventWatcherPrintsOnlyNewEvents(t *testing.T) {
+	pages := []string{
+		`{"events":[{"id":"00000000-0000-4000-8000-000000000002"},{"id":"00000000-0000-4000-8000-000000000001"}]}`,
+		`{"events":[{"id":"00000000-0000-4000-8000-000000000003"},{"id":"00000000-0000-4000-8000-000000000002"}]}`,
+	}
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(pages[min(calls, len(pages)-1)]))
+		calls++
+	}))
+	defer srv.Close()
+	useServer(t, srv.URL)
+	useOutput(t, "json", false)
+
+	w := newEventWatcher()
+	out := captureStdout(t, func() {
+		for range pages {
+			if err := w.poll(); err != nil {
+				t.Fatalf("poll: %v", err)
+			}
+		}
+	})
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("printed %d events, want 3:\n%s", len(lines), out)
+	}
+	for i, suffix := range []string{"1", "2", "3"} {
+		if !strings.Contains(lines[i], "00000000000"+suffix+`"`) {
+			t.Errorf("line %d = %s, want event ...%s", i, lines[i], suffix)
+		}
+	}
+}
+
+func TestWatchStopsWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	polls := 0
+	err := watch(ctx, func() error {
+		polls++
+		cancel()
+		return nil
+	})
+	if err != nil || polls != 1 {
+		t.Errorf("watch = %v after %d polls, want nil after 1", err, polls)
+	}
+
+	wantErr := errors.New("unreachable")
+	if err := watch(context.Background(), func() error { return wantErr }); err != wantErr {
+		t.Errorf("watch error = %v, want poll error", err)
+	}
+}
+
+func TestStreamEventsParsesMessages(t *testing.T) {
+	var gotLastID, gotQuery string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotLastID = r.Header.Get("Last-Event-ID")
+		gotQuery = r.URL.RawQuery
+		w.Header().Set("Content-Type", "text/event-stream")
+		io.WriteString(w, "retry: 2000\n\n: ping\n\n")
+		io.WriteString(w, "id: 00000000-0000-4000-8000-000000000001\nevent: event\n"+
+			`data: {"type":"event","event":{"id":"00000000-0000-4000-8000-000000000001","channel_id":"grafana","status":"received"}}`+"\n\n")
+		io.WriteString(w, "event: status\n"+
+			`data: {"type":"status","event":{"id":"00000000-0000-4000-8000-000000000001"},"change":{"status":"failed","error":"boom"}}`+"\n\n")
+	}))
+	defer srv.Close()
+	useServer(t, srv.URL)
+
+	var got []event.Notification
+	lastID := "00000000-0000-4000-8000-000000000000"
+	query := url.Values{"channel": {"grafana"}}
+	err := streamEvents(context.Background(), query, &lastID, func(n event.Notification) error {
+		got = append(got, n)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("streamEvents: %v", err)
+	}
+	if gotLastID != "00000000-0000-4000-8000-000000000000" || gotQuery != "channel=grafana" {
+		t.Errorf("request Last-Event-ID = %q, query = %q", gotLastID, gotQuery)
+	}
+	if len(got) != 2 {
+		t.Fatalf("received %d messages, want 2", len(got))
+	}
+	if got[0].Type != event.NotifySaved || got[0].Event.ChannelID != "grafana" {
+		t.Errorf("first message = %+v", got[0])
+	}
+	if got[1].Change == nil || got[1].Change.Error != "boom" {
+		t.Errorf("second message change = %+v", got[1].Change)
+	}
+	// Status messages carry no id, so the resume point stays at the last event.
+	if lastID != "00000000-0000-4000-8000-000000000001" {
+		t.Errorf("lastID = %q, want the last event's ID", lastID)
+	}
+}
+
+func TestStreamEventsServerError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":"invalid Last-Event-ID"}`))
+	}))
+	defer srv.Close()
+	useServer(t, srv.URL)
+
+	lastID := "bogus"
+	err := streamEvents(context.Background(), nil, &lastID, func(event.Notification) error { return nil })
+	var apiErr *adminError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("error = %v, want 400 admin error", err)
+	}
+}
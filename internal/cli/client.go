@@ -0,0 +1,137 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/youmna-rabie/claude-pod/internal/config"
+)
+
+// adminTimeout bounds a single admin API call. It is generous because
+// replays wait for the agent.
+const adminTimeout = 5 * time.Minute
+
+// replayEventTimeout is allowed for each event of a bulk replay request,
+// which forwards its events one after another. It covers a forward with the
+// default agent timeout, retries and backoff.
+const replayEventTimeout = 2 * time.Minute
+
+var (
+	serverAddr string
+	adminToken string
+)
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&serverAddr, "server", "", "gateway address for admin API commands (default: admin or server address from --config)")
+	rootCmd.PersistentFlags().StringVar(&adminToken, "token", os.Getenv("GATEWAY_ADMIN_TOKEN"), "admin API key for admin API commands (default: $GATEWAY_ADMIN_TOKEN)")
+}
+
+// setAdminAuth adds the admin API key to req, if one was given.
+func setAdminAuth(req *http.Request) {
+	if adminToken != "" {
+		req.Header.Set("Authorization", "Bearer "+adminToken)
+	}
+}
+
+// adminError is a non-2xx response from the admin API.
+type adminError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *adminError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("gateway returned status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("gateway returned status %d: %s", e.StatusCode, e.Message)
+}
+
+// adminURL returns the base URL of the gateway admin API: --server if set,
+// otherwise the admin listener from the config file, falling back to the
+// server address when the admin API shares it.
+func adminURL() (string, error) {
+	if serverAddr != "" {
+		if strings.Contains(serverAddr, "://") {
+			return strings.TrimSuffix(serverAddr, "/"), nil
+		}
+		return "http://" + strings.TrimSuffix(serverAddr, "/"), nil
+	}
+
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return "", fmt.Errorf("loading config: %w", err)
+	}
+	host, port := cfg.Server.Host, cfg.Server.Port
+	if cfg.Admin.Port != 0 {
+		host, port = cfg.Admin.Host, cfg.Admin.Port
+	}
+	switch host {
+	case "", "0.0.0.0", "::":
+		host = "127.0.0.1"
+	}
+	return "http://" + net.JoinHostPort(host, strconv.Itoa(port)), nil
+}
+
+// callAdmin sends a request to the admin API and decodes the JSON response
+// into out. Error responses are still decoded into out when possible, and
+// reported as *adminError.
+func callAdmin(method, path string, query url.Values, out any) error {
+	return callAdminTimeout(method, path, query, out, adminTimeout)
+}
+
+// callAdminTimeout is callAdmin with the call bounded by timeout instead of
+// adminTimeout.
+func callAdminTimeout(method, path string, query url.Values, out any, timeout time.Duration) error {
+	base, err := adminURL()
+	if err != nil {
+		return err
+	}
+	target := base + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequest(method, target, nil)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	setAdminAuth(req)
+
+	client := &http.Client{Timeout: timeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("calling gateway: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("reading response: %w", err)
+	}
+
+	if resp.StatusCode >= 400 {
+		var msg struct {
+			Error string `json:"error"`
+		}
+		_ = json.Unmarshal(body, &msg)
+		if out != nil {
+			_ = json.Unmarshal(body, out)
+		}
+		return &adminError{StatusCode: resp.StatusCode, Message: msg.Error}
+	}
+	if out != nil {
+		if err := json.Unmarshal(body, out); err != nil {
+			return fmt.Errorf("decoding response: %w", err)
+		}
+	}
+	return nil
+}
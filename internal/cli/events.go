@@ -1,46 +1,136 @@
 package cli
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/http"
 
+	"github.com/google/uuid"
 	"github.com/spf13/cobra"
-	"github.com/youmna-rabie/claude-pod/internal/event"
+	"github.com/youmna-rabie/claude-pod/internal/types"
 )
 
-var eventsLimit int
+var eventsFilters eventFilters
 
 func init() {
-	listEventsCmd.Flags().IntVar(&eventsLimit, "limit", 20, "maximum number of events to display")
+	eventsFilters.register(listEventsCmd, 20)
+	registerOutputFlags(listEventsCmd)
 	rootCmd.AddCommand(listEventsCmd)
 }
 
 var listEventsCmd = &cobra.Command{
 	Use:   "list-events",
-	Short: "Print recent events from store",
-	RunE:  listEvents,
+	Short: "Print recent events from a running gateway",
+	Long: "Query the admin API of a running gateway for events, newest first.\n" +
+		"With --watch, new events are printed as they arrive.",
+	RunE: listEvents,
+}
+
+// eventsPage mirrors the GET /admin/events response.
+type eventsPage struct {
+	Events     []types.Event `json:"events"`
+	NextCursor string        `json:"next_cursor"`
 }
 
 func listEvents(cmd *cobra.Command, args []string) error {
-	// Events are in-memory only, so we create a fresh store and show it's empty.
-	// A running gateway would need an API call; for now we demonstrate the store query.
-	store, err := event.NewMemoryStore(1000)
-	if err != nil {
-		return fmt.Errorf("creating store: %w", err)
+	if err := validateOutput(); err != nil {
+		return err
+	}
+	if watchMode {
+		ctx, stop := interruptContext()
+		defer stop()
+		return watch(ctx, newEventWatcher().poll)
 	}
 
-	events, err := store.List(eventsLimit, 0)
-	if err != nil {
-		return fmt.Errorf("listing events: %w", err)
+	var raw json.RawMessage
+	if err := callAdmin(http.MethodGet, "/admin/events", eventsFilters.values(), &raw); err != nil {
+		return err
+	}
+	var page eventsPage
+	if err := json.Unmarshal(raw, &page); err != nil {
+		return fmt.Errorf("decoding response: %w", err)
 	}
 
-	if len(events) == 0 {
-		fmt.Println("No events found. (Start the gateway to receive events.)")
+	return printOutput(raw, func() error {
+		if len(page.Events) == 0 {
+			fmt.Println("No events found.")
+			return nil
+		}
+		printEventHeader()
+		for _, e := range page.Events {
+			printEventRow(e)
+		}
+		if page.NextCursor != "" {
+			fmt.Printf("\nMore events: --cursor %s\n", page.NextCursor)
+		}
 		return nil
+	})
+}
+
+// eventWatcher polls for events and prints those not shown by the previous poll.
+type eventWatcher struct {
+	seen    map[uuid.UUID]bool
+	started bool
+}
+
+func newEventWatcher() *eventWatcher {
+	return &eventWatcher{seen: map[uuid.UUID]bool{}}
+}
+
+// poll prints new events oldest-first. Only the previous poll's IDs are
+// remembered: anything older has dropped off the newest-first page anyway.
+func (w *eventWatcher) poll() error {
+	var page eventsPage
+	if err := callAdmin(http.MethodGet, "/admin/events", eventsFilters.values(), &page); err != nil {
+		return err
 	}
 
-	fmt.Printf("%-36s  %-15s  %-12s  %s\n", "ID", "CHANNEL", "STATUS", "TIMESTAMP")
-	for _, e := range events {
-		fmt.Printf("%-36s  %-15s  %-12s  %s\n", e.ID, e.ChannelID, e.Status, e.Timestamp.Format("2006-01-02 15:04:05"))
+	seen := make(map[uuid.UUID]bool, len(page.Events))
+	for i := len(page.Events) - 1; i >= 0; i-- {
+		e := page.Events[i]
+		seen[e.ID] = true
+		if w.seen[e.ID] {
+			continue
+		}
+		if err := printWatchedEvent(e, !w.started); err != nil {
+			return err
+		}
+		w.started = true
 	}
+	w.seen = seen
 	return nil
 }
+
+// printWatchedEvent prints one event as a table row, a JSON line, or a YAML document.
+func printWatchedEvent(e types.Event, first bool) error {
+	switch outputFormat {
+	case "json":
+		data, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	case "yaml":
+		data, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		fmt.Println("---")
+		return printOutput(data, nil)
+	default:
+		if first {
+			printEventHeader()
+		}
+		printEventRow(e)
+		return nil
+	}
+}
+
+func printEventHeader() {
+	fmt.Printf("%-36s  %-15s  %-13s  %s\n", "ID", "CHANNEL", "STATUS", "TIMESTAMP")
+}
+
+func printEventRow(e types.Event) {
+	fmt.Printf("%-36s  %-15s  %-13s  %s\n", e.ID, e.ChannelID, e.Status, e.Timestamp.Format("2006-01-02 15:04:05"))
+}
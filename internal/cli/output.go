@@ -0,0 +1,111 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// Output and watch settings shared by the list commands.
+var (
+	outputFormat  string
+	watchMode     bool
+	watchInterval time.Duration
+	remoteMode    bool
+)
+
+// registerOutputFlags adds --output, --watch and --interval to cmd.
+func registerOutputFlags(cmd *cobra.Command) {
+	flags := cmd.Flags()
+	flags.StringVarP(&outputFormat, "output", "o", "table", "output format: table, json or yaml")
+	flags.BoolVarP(&watchMode, "watch", "w", false, "keep polling the gateway and print updates until interrupted")
+	flags.DurationVar(&watchInterval, "interval", 2*time.Second, "polling interval for --watch")
+}
+
+// registerRemoteFlag adds --remote to commands that read the local config by
+// default but can ask a running gateway instead. --server implies --remote.
+func registerRemoteFlag(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&remoteMode, "remote", false, "query the running gateway's admin API instead of the local config")
+}
+
+// useRemote reports whether a command with a local fallback should call the admin API.
+func useRemote() bool {
+	return remoteMode || serverAddr != "" || watchMode
+}
+
+// validateOutput rejects unknown --output values before any work is done.
+func validateOutput() error {
+	switch outputFormat {
+	case "", "table", "json", "yaml":
+		return nil
+	default:
+		return fmt.Errorf("unsupported output format %q (want table, json or yaml)", outputFormat)
+	}
+}
+
+// printOutput writes data, a JSON document, in the selected format. The
+// table format is delegated to table.
+func printOutput(data json.RawMessage, table func() error) error {
+	switch outputFormat {
+	case "", "table":
+		return table()
+	case "json":
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, data, "", "  "); err != nil {
+			return fmt.Errorf("formatting JSON: %w", err)
+		}
+		fmt.Println(buf.String())
+		return nil
+	case "yaml":
+		// Go through a generic value so YAML keys match the JSON field names.
+		var v any
+		if err := json.Unmarshal(data, &v); err != nil {
+			return fmt.Errorf("decoding response: %w", err)
+		}
+		out, err := yaml.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("formatting YAML: %w", err)
+		}
+		fmt.Print(string(out))
+		return nil
+	default:
+		return validateOutput()
+	}
+}
+
+// watch calls poll immediately and then every watchInterval until ctx is
+// done or poll fails.
+func watch(ctx context.Context, poll func() error) error {
+	ticker := time.NewTicker(watchInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := poll(); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// interruptContext returns a context cancelled on SIGINT or SIGTERM, so
+// --watch exits cleanly.
+func interruptContext() (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+}
+
+// clearScreen moves the cursor home and clears the terminal before a table redraw.
+func clearScreen() {
+	fmt.Print("\033[H\033[2J")
+}
@@ -0,0 +1,145 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/youmna-rabie/claude-pod/internal/event"
+)
+
+// eventFilters holds the event query flags shared by commands that select
+// events through the admin API.
+type eventFilters struct {
+	channel, status, since, until, header, body, cursor string
+	limit                                               int
+}
+
+// register adds the filter flags to cmd with the given default limit.
+func (f *eventFilters) register(cmd *cobra.Command, limit int) {
+	flags := cmd.Flags()
+	flags.StringVar(&f.channel, "channel", "", "only events from this channel")
+	flags.StringVar(&f.status, "status", "", "only events with this status (received, forwarded, failed, dead_lettered, completed)")
+	flags.StringVar(&f.since, "since", "", "only events at or after this RFC 3339 time or duration ago, e.g. 1h")
+	flags.StringVar(&f.until, "until", "", "only events before this RFC 3339 time or duration ago")
+	flags.StringVar(&f.header, "header", "", "only events with a header containing this text")
+	flags.StringVar(&f.body, "body", "", "only events whose body contains this text")
+	flags.IntVar(&f.limit, "limit", limit, "maximum number of events per request")
+	flags.StringVar(&f.cursor, "cursor", "", "continue from the next_cursor of a previous page")
+}
+
+// values encodes the filters as admin API query parameters.
+func (f *eventFilters) values() url.Values {
+	v := url.Values{}
+	for key, val := range map[string]string{
+		"channel": f.channel,
+		"status":  f.status,
+		"since":   f.since,
+		"until":   f.until,
+		"header":  f.header,
+		"body":    f.body,
+		"cursor":  f.cursor,
+	} {
+		if val != "" {
+			v.Set(key, val)
+		}
+	}
+	if f.limit > 0 {
+		v.Set("limit", strconv.Itoa(f.limit))
+	}
+	return v
+}
+
+var replayFilters eventFilters
+
+func init() {
+	replayFilters.register(replayCmd, 50)
+	rootCmd.AddCommand(replayCmd)
+}
+
+var replayCmd = &cobra.Command{
+	Use:   "replay [event-id]",
+	Short: "Re-send stored events to the agent",
+	Long: "Replay one event by ID, or every event matching the filter flags, through a running gateway.\n" +
+		"Bulk replays require at least one filter and run oldest first, in batches of --limit events.",
+	Args: cobra.MaximumNArgs(1),
+	RunE: replayEvents,
+}
+
+// replayResult mirrors the admin API's per-event replay outcome.
+type replayResult struct {
+	EventID  string `json:"event_id"`
+	Status   string `json:"status"`
+	Attempts int    `json:"attempts"`
+	Error    string `json:"error"`
+}
+
+func replayEvents(cmd *cobra.Command, args []string) error {
+	if len(args) == 1 {
+		var result replayResult
+		err := callAdmin(http.MethodPost, "/admin/events/"+url.PathEscape(args[0])+"/replay", nil, &result)
+		var apiErr *adminError
+		if err != nil && (!errors.As(err, &apiErr) || result.EventID == "") {
+			return err
+		}
+		printReplayResults([]replayResult{result})
+		if result.Status != "forwarded" {
+			return fmt.Errorf("replay of %s failed", result.EventID)
+		}
+		return nil
+	}
+
+	var (
+		results []replayResult
+		failed  int
+	)
+	query := replayFilters.values()
+	for {
+		var page struct {
+			Results    []replayResult `json:"results"`
+			Failed     int            `json:"failed"`
+			NextCursor string         `json:"next_cursor"`
+		}
+		if err := callAdminTimeout(http.MethodPost, "/admin/replay", query, &page, replayTimeout(replayFilters.limit)); err != nil {
+			return err
+		}
+		results = append(results, page.Results...)
+		failed += page.Failed
+		if page.NextCursor == "" {
+			break
+		}
+		query.Set("cursor", page.NextCursor)
+	}
+
+	if len(results) == 0 {
+		fmt.Println("No matching events to replay.")
+		return nil
+	}
+	printReplayResults(results)
+	fmt.Printf("\nReplayed %d events: %d succeeded, %d failed.\n", len(results), len(results)-failed, failed)
+	if failed > 0 {
+		return fmt.Errorf("%d of %d replays failed", failed, len(results))
+	}
+	return nil
+}
+
+// replayTimeout bounds a bulk replay request for up to limit events, which
+// the gateway forwards one after another before responding.
+func replayTimeout(limit int) time.Duration {
+	if limit <= 0 {
+		limit = event.DefaultQueryLimit
+	}
+	limit = min(limit, event.MaxQueryLimit)
+	return max(adminTimeout, time.Duration(limit)*replayEventTimeout)
+}
+
+func printReplayResults(results []replayResult) {
+	fmt.Printf("%-36s  %-10s  %-8s  %s\n", "ID", "STATUS", "ATTEMPTS", "ERROR")
+	for _, r := range results {
+		fmt.Printf("%-36s  %-10s  %-8d  %s\n", r.EventID, r.Status, r.Attempts, r.Error)
+	}
+}
@@ -3,6 +3,7 @@ package cli
 import (
 	"context"
 	"fmt"
+	"io"
 	"log/slog"
 	"net"
 	"net/http"
@@ -16,8 +17,10 @@ import (
 	"github.com/youmna-rabie/claude-pod/internal/channel"
 	"github.com/youmna-rabie/claude-pod/internal/config"
 	"github.com/youmna-rabie/claude-pod/internal/event"
+	"github.com/youmna-rabie/claude-pod/internal/metrics"
 	"github.com/youmna-rabie/claude-pod/internal/server"
 	"github.com/youmna-rabie/claude-pod/internal/skill"
+	"github.com/youmna-rabie/claude-pod/internal/tracing"
 	"github.com/youmna-rabie/claude-pod/internal/types"
 )
 
@@ -39,14 +42,37 @@ func runGateway(cmd *cobra.Command, args []string) error {
 
 	logger := newLogger(cfg.Logging)
 
-	store, err := event.NewMemoryStore(cfg.Store.Capacity)
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing)
+	if err != nil {
+		return fmt.Errorf("setting up tracing: %w", err)
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			logger.Warn("flushing traces failed", "error", err)
+		}
+	}()
+
+	store, err := newStore(cfg.Store, logger)
 	if err != nil {
 		return fmt.Errorf("creating store: %w", err)
 	}
+	if c, ok := store.(io.Closer); ok {
+		defer c.Close()
+	}
+
+	deadLetters, err := newStore(cfg.DeadLetter, logger)
+	if err != nil {
+		return fmt.Errorf("creating dead-letter queue: %w", err)
+	}
+	if c, ok := deadLetters.(io.Closer); ok {
+		defer c.Close()
+	}
 
 	channels := buildChannels(cfg.Channels)
 
-	agentClient := &agent.StubClient{Logger: logger}
+	agentClient, breaker := newAgentClient(cfg.Agent, logger)
 
 	reg := &skill.Registry{}
 	if len(cfg.Skills.Dirs) > 0 {
@@ -56,7 +82,29 @@ func runGateway(cmd *cobra.Command, args []string) error {
 	}
 	skills := reg.Filter(cfg.Skills.Allowlist)
 
-	srv := server.NewServer(cfg, store, channels, agentClient, skills, logger)
+	audit := logger
+	if cfg.Admin.AuditLog != "" {
+		f, err := os.OpenFile(cfg.Admin.AuditLog, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+		if err != nil {
+			return fmt.Errorf("opening admin audit log: %w", err)
+		}
+		defer f.Close()
+		audit = slog.New(slog.NewJSONHandler(f, nil))
+	}
+	if len(cfg.Admin.Keys) == 0 {
+		logger.Warn("admin API has no keys configured and is open to anyone who can reach it")
+	}
+
+	m := metrics.New()
+	m.RegisterStore("events", store)
+	m.RegisterStore("dead_letter", deadLetters)
+
+	srv := server.NewServer(cfg, store, channels, agentClient, skills, logger,
+		server.WithBreaker(breaker),
+		server.WithDeadLetters(deadLetters),
+		server.WithMetrics(m),
+		server.WithAuditLog(audit),
+	)
 
 	addr := net.JoinHostPort(cfg.Server.Host, fmt.Sprintf("%d", cfg.Server.Port))
 	httpSrv := &http.Server{
@@ -64,16 +112,34 @@ func runGateway(cmd *cobra.Command, args []string) error {
 		Handler:           srv,
 		ReadHeaderTimeout: 10 * time.Second,
 	}
+	httpSrv.RegisterOnShutdown(srv.CloseStreams)
+
+	// The admin API gets its own listener when admin.port is set.
+	var adminSrv *http.Server
+	if h := srv.AdminHandler(); h != nil {
+		adminSrv = &http.Server{
+			Addr:              net.JoinHostPort(cfg.Admin.Host, fmt.Sprintf("%d", cfg.Admin.Port)),
+			Handler:           h,
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+		adminSrv.RegisterOnShutdown(srv.CloseStreams)
+	}
 
 	// Graceful shutdown on SIGINT/SIGTERM.
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
 
-	errCh := make(chan error, 1)
+	errCh := make(chan error, 2)
 	go func() {
 		logger.Info("server starting", "addr", addr)
 		errCh <- httpSrv.ListenAndServe()
 	}()
+	if adminSrv != nil {
+		go func() {
+			logger.Info("admin server starting", "addr", adminSrv.Addr)
+			errCh <- adminSrv.ListenAndServe()
+		}()
+	}
 
 	select {
 	case err := <-errCh:
@@ -84,8 +150,20 @@ func runGateway(cmd *cobra.Command, args []string) error {
 		logger.Info("shutting down gracefully")
 		shutCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
-		if err := httpSrv.Shutdown(shutCtx); err != nil {
-			return fmt.Errorf("shutdown error: %w", err)
+		// Stop accepting connections and wait for handlers, then drain the
+		// async queue. If the deadline passes first, srv.Shutdown cancels the
+		// remaining forwards so their events are marked failed before exit.
+		httpErr := httpSrv.Shutdown(shutCtx)
+		if adminSrv != nil {
+			if err := adminSrv.Shutdown(shutCtx); err != nil && httpErr == nil {
+				httpErr = err
+			}
+		}
+		if err := srv.Shutdown(shutCtx); err != nil {
+			logger.Warn("in-flight forwards cancelled at shutdown", "error", err)
+		}
+		if httpErr != nil {
+			return fmt.Errorf("shutdown error: %w", httpErr)
 		}
 	}
 
@@ -99,6 +177,24 @@ func buildChannels(cfgs []config.ChannelConfig) map[string]types.Channel {
 		switch ch.Type {
 		case "grafana":
 			channels[ch.Name] = channel.NewGrafanaChannel(ch.Name, ch.Auth)
+		case "alertmanager":
+			channels[ch.Name] = channel.NewAlertmanagerChannel(ch.Name, ch.Auth, ch.BasicAuth.Username, ch.BasicAuth.Password)
+		case "github":
+			channels[ch.Name] = channel.NewGitHubChannel(ch.Name, ch.Secret, ch.Events)
+		case "slack":
+			channels[ch.Name] = channel.NewSlackChannel(ch.Name, ch.Secret)
+		case "hmac":
+			channels[ch.Name] = channel.NewHMACChannel(ch.Name, channel.HMACOptions{
+				Algorithm:       ch.HMAC.Algorithm,
+				Header:          ch.HMAC.Header,
+				Prefix:          ch.HMAC.Prefix,
+				Encoding:        ch.HMAC.Encoding,
+				Secrets:         ch.HMAC.Secrets,
+				TimestampHeader: ch.HMAC.TimestampHeader,
+				Tolerance:       ch.HMAC.Tolerance,
+			})
+		case "cloudevents":
+			channels[ch.Name] = channel.NewCloudEventsChannel(ch.Name, ch.Auth)
 		default:
 			channels[ch.Name] = channel.NewDummyChannel(ch.Name)
 		}
@@ -106,6 +202,52 @@ func buildChannels(cfgs []config.ChannelConfig) map[string]types.Channel {
 	return channels
 }
 
+func newStore(cfg config.StoreConfig, logger *slog.Logger) (event.Store, error) {
+	switch cfg.Type {
+	case "memory":
+		return event.NewMemoryStore(cfg.Capacity)
+	case "sqlite":
+		return event.NewSQLiteStore(cfg.Path, cfg.Capacity)
+	case "file":
+		return event.NewFileStore(cfg.Path, event.FileOptions{
+			Capacity:    cfg.Capacity,
+			SegmentSize: cfg.SegmentSize,
+			Retention:   cfg.Retention,
+			Logger:      logger,
+		})
+	default:
+		return nil, fmt.Errorf("unsupported store type %q", cfg.Type)
+	}
+}
+
+// newAgentClient builds the agent client chain: the base client, wrapped in
+// retries, wrapped in a circuit breaker so an unhealthy agent fails fast.
+func newAgentClient(cfg config.AgentConfig, logger *slog.Logger) (agent.Client, *agent.Breaker) {
+	var client agent.Client
+	switch cfg.Type {
+	case "http":
+		client = agent.NewHTTPClient(cfg.URL, cfg.Timeout)
+	default:
+		client = &agent.StubClient{Logger: logger}
+	}
+
+	if cfg.Retry.MaxAttempts > 1 {
+		client = agent.NewRetryClient(client, agent.RetryPolicy{
+			MaxAttempts: cfg.Retry.MaxAttempts,
+			BaseBackoff: cfg.Retry.BaseBackoff,
+			MaxBackoff:  cfg.Retry.MaxBackoff,
+			Jitter:      cfg.Retry.Jitter,
+		}, logger)
+	}
+
+	breaker := agent.NewBreaker(client, agent.BreakerPolicy{
+		FailureThreshold: cfg.Breaker.FailureThreshold,
+		Cooldown:         cfg.Breaker.Cooldown,
+		HalfOpenRequests: cfg.Breaker.HalfOpenRequests,
+	}, logger)
+	return breaker, breaker
+}
+
 func newLogger(cfg config.LoggingConfig) *slog.Logger {
 	var handler slog.Handler
 	opts := &slog.HandlerOptions{Level: parseLogLevel(cfg.Level)}
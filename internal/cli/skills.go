@@ -1,24 +1,51 @@
 package cli
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/http"
 
 	"github.com/spf13/cobra"
 	"github.com/youmna-rabie/claude-pod/internal/config"
 	"github.com/youmna-rabie/claude-pod/internal/skill"
+	"github.com/youmna-rabie/claude-pod/internal/types"
 )
 
 func init() {
+	registerOutputFlags(listSkillsCmd)
+	registerRemoteFlag(listSkillsCmd)
 	rootCmd.AddCommand(listSkillsCmd)
 }
 
 var listSkillsCmd = &cobra.Command{
 	Use:   "list-skills",
 	Short: "Print registered skills",
-	RunE:  listSkills,
+	Long: "Print the skills found from the local config, or with --remote, --server\n" +
+		"or --watch, the skills loaded by a running gateway.",
+	RunE: listSkills,
 }
 
 func listSkills(cmd *cobra.Command, args []string) error {
+	if err := validateOutput(); err != nil {
+		return err
+	}
+	if !useRemote() {
+		return listLocalSkills()
+	}
+	if watchMode {
+		ctx, stop := interruptContext()
+		defer stop()
+		return watch(ctx, func() error {
+			if outputFormat == "" || outputFormat == "table" {
+				clearScreen()
+			}
+			return listRemoteSkills()
+		})
+	}
+	return listRemoteSkills()
+}
+
+func listLocalSkills() error {
 	cfg, err := config.Load(configPath)
 	if err != nil {
 		return fmt.Errorf("loading config: %w", err)
@@ -32,15 +59,41 @@ func listSkills(cmd *cobra.Command, args []string) error {
 	}
 
 	skills := reg.Filter(cfg.Skills.Allowlist)
+	data, err := json.Marshal(map[string]any{"skills": skills, "count": len(skills)})
+	if err != nil {
+		return err
+	}
+	return printOutput(data, func() error {
+		printSkills(skills)
+		return nil
+	})
+}
+
+func listRemoteSkills() error {
+	var raw json.RawMessage
+	if err := callAdmin(http.MethodGet, "/admin/skills", nil, &raw); err != nil {
+		return err
+	}
+	var resp struct {
+		Skills []types.Skill `json:"skills"`
+	}
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return fmt.Errorf("decoding response: %w", err)
+	}
+	return printOutput(raw, func() error {
+		printSkills(resp.Skills)
+		return nil
+	})
+}
 
+func printSkills(skills []types.Skill) {
 	if len(skills) == 0 {
 		fmt.Println("No skills registered.")
-		return nil
+		return
 	}
 
 	fmt.Printf("%-20s %-40s %s\n", "NAME", "DESCRIPTION", "PATH")
 	for _, s := range skills {
 		fmt.Printf("%-20s %-40s %s\n", s.Name, s.Description, s.Path)
 	}
-	return nil
 }
@@ -0,0 +1,195 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/youmna-rabie/claude-pod/internal/event"
+)
+
+// tailReconnectDelay is how long tail waits before reconnecting a dropped stream.
+var tailReconnectDelay = 2 * time.Second
+
+var tailFilters struct {
+	channel, status string
+}
+
+func init() {
+	flags := tailCmd.Flags()
+	flags.StringVar(&tailFilters.channel, "channel", "", "only events from this channel")
+	flags.StringVar(&tailFilters.status, "status", "", "only events and status changes with this status")
+	flags.StringVarP(&outputFormat, "output", "o", "table", "output format: table, json or yaml")
+	rootCmd.AddCommand(tailCmd)
+}
+
+var tailCmd = &cobra.Command{
+	Use:   "tail",
+	Short: "Stream events and status changes from a running gateway",
+	Long: "Follow GET /admin/events/stream on a running gateway until interrupted.\n" +
+		"If the connection drops, tail reconnects and resumes after the last event it printed.",
+	RunE: tailEvents,
+}
+
+func tailEvents(cmd *cobra.Command, args []string) error {
+	if err := validateOutput(); err != nil {
+		return err
+	}
+	ctx, stop := interruptContext()
+	defer stop()
+
+	query := url.Values{}
+	if tailFilters.channel != "" {
+		query.Set("channel", tailFilters.channel)
+	}
+	if tailFilters.status != "" {
+		query.Set("status", tailFilters.status)
+	}
+
+	p := &tailPrinter{}
+	var lastID string
+	for {
+		err := streamEvents(ctx, query, &lastID, p.print)
+		if ctx.Err() != nil {
+			return nil
+		}
+		var apiErr *adminError
+		if errors.As(err, &apiErr) {
+			return err
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "stream interrupted: %v; reconnecting\n", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(tailReconnectDelay):
+		}
+	}
+}
+
+// streamEvents reads the admin event stream, calling fn for each message,
+// until the server closes it or ctx is done. *lastID is sent as
+// Last-Event-ID and updated as messages with an id arrive.
+func streamEvents(ctx context.Context, query url.Values, lastID *string, fn func(event.Notification) error) error {
+	base, err := adminURL()
+	if err != nil {
+		return err
+	}
+	target := base + "/admin/events/stream"
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	setAdminAuth(req)
+	if *lastID != "" {
+		req.Header.Set("Last-Event-ID", *lastID)
+	}
+
+	// No client timeout: the stream stays open until either side closes it.
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("calling gateway: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		var msg struct {
+			Error string `json:"error"`
+		}
+		body, _ := io.ReadAll(resp.Body)
+		_ = json.Unmarshal(body, &msg)
+		return &adminError{StatusCode: resp.StatusCode, Message: msg.Error}
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+	var id string
+	var data strings.Builder
+	for scanner.Scan() {
+		line := scanner.Text()
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+
+		switch {
+		case line == "":
+			if data.Len() > 0 {
+				var n event.Notification
+				if err := json.Unmarshal([]byte(data.String()), &n); err != nil {
+					return fmt.Errorf("decoding stream message: %w", err)
+				}
+				if err := fn(n); err != nil {
+					return err
+				}
+			}
+			if id != "" {
+				*lastID = id
+			}
+			id = ""
+			data.Reset()
+		case field == "id":
+			id = value
+		case field == "data":
+			if data.Len() > 0 {
+				data.WriteByte('\n')
+			}
+			data.WriteString(value)
+		}
+	}
+	if err := scanner.Err(); err != nil && ctx.Err() == nil {
+		return fmt.Errorf("reading stream: %w", err)
+	}
+	return nil
+}
+
+// tailPrinter prints stream messages in the selected output format.
+type tailPrinter struct {
+	started bool
+}
+
+func (p *tailPrinter) print(n event.Notification) error {
+	switch outputFormat {
+	case "json":
+		data, err := json.Marshal(n)
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	case "yaml":
+		data, err := json.Marshal(n)
+		if err != nil {
+			return err
+		}
+		fmt.Println("---")
+		return printOutput(data, nil)
+	}
+
+	if !p.started {
+		fmt.Printf("%-19s  %-6s  %-36s  %-15s  %-13s  %s\n", "TIME", "TYPE", "ID", "CHANNEL", "STATUS", "ERROR")
+		p.started = true
+	}
+	ts, status, detail := n.Event.Timestamp, n.Event.Status, ""
+	if n.Change != nil {
+		ts, status, detail = n.Change.Timestamp, n.Change.Status, n.Change.Error
+	}
+	fmt.Printf("%-19s  %-6s  %-36s  %-15s  %-13s  %s\n",
+		ts.Format("2006-01-02 15:04:05"), n.Type, n.Event.ID, n.Event.ChannelID, status, detail)
+	return nil
+}
@@ -2,7 +2,10 @@ package config
 
 import (
 	"fmt"
+	"math"
 	"os"
+	"regexp"
+	"strings"
 	"time"
 
 	"gopkg.in/yaml.v3"
@@ -10,24 +13,47 @@ import (
 
 // Config is the top-level gateway configuration.
 type Config struct {
-	Server   ServerConfig   `yaml:"server"`
-	Agent    AgentConfig    `yaml:"agent"`
-	Channels []ChannelConfig `yaml:"channels"`
-	Skills   SkillsConfig   `yaml:"skills"`
-	Store    StoreConfig    `yaml:"store"`
-	Logging  LoggingConfig  `yaml:"logging"`
+	Server     ServerConfig    `yaml:"server"`
+	Agent      AgentConfig     `yaml:"agent"`
+	Channels   []ChannelConfig `yaml:"channels"`
+	Skills     SkillsConfig    `yaml:"skills"`
+	Store      StoreConfig     `yaml:"store"`
+	DeadLetter StoreConfig     `yaml:"dead_letter"` // events that failed every forward attempt
+	Logging    LoggingConfig   `yaml:"logging"`
+	Tracing    TracingConfig   `yaml:"tracing"`
+	Admin      AdminConfig     `yaml:"admin"`
 }
 
 // ServerConfig holds HTTP listener settings.
 type ServerConfig struct {
-	Host string `yaml:"host"`
-	Port int    `yaml:"port"`
+	Host      string `yaml:"host"`
+	Port      int    `yaml:"port"`
+	Workers   int    `yaml:"workers"`    // async forwarding workers
+	QueueSize int    `yaml:"queue_size"` // async events buffered before backpressure
 }
 
 // AgentConfig holds upstream agent connection settings.
 type AgentConfig struct {
+	Type    string        `yaml:"type"` // "stub" or "http"
 	URL     string        `yaml:"url"`
 	Timeout time.Duration `yaml:"timeout"`
+	Retry   RetryConfig   `yaml:"retry"`
+	Breaker BreakerConfig `yaml:"breaker"`
+}
+
+// RetryConfig controls retries of failed agent forwards.
+type RetryConfig struct {
+	MaxAttempts int           `yaml:"max_attempts"`
+	BaseBackoff time.Duration `yaml:"base_backoff"`
+	MaxBackoff  time.Duration `yaml:"max_backoff"`
+	Jitter      float64       `yaml:"jitter"`
+}
+
+// BreakerConfig controls the circuit breaker around the agent client.
+type BreakerConfig struct {
+	FailureThreshold int           `yaml:"failure_threshold"`
+	Cooldown         time.Duration `yaml:"cooldown"`
+	HalfOpenRequests int           `yaml:"half_open_requests"`
 }
 
 // ChannelConfig describes a single inbound channel.
@@ -35,6 +61,66 @@ type ChannelConfig struct {
 	Name string `yaml:"name"`
 	Type string `yaml:"type"`
 	Auth string `yaml:"auth"`
+	Mode string `yaml:"mode"` // "sync" (wait for the agent) or "async" (queue and reply 202)
+
+	RateLimit RateLimitConfig `yaml:"rate_limit"`
+	Headers   HeaderConfig    `yaml:"headers"`
+	Redact    RedactConfig    `yaml:"redact"`
+
+	BasicAuth BasicAuthConfig `yaml:"basic_auth"` // alertmanager: accepted instead of, or as well as, the auth bearer token
+	Secret    string          `yaml:"secret"`     // github, slack: signing secret for signature verification
+	Events    []string        `yaml:"events"`     // github: event types to accept; empty accepts all
+	HMAC      HMACConfig      `yaml:"hmac"`       // hmac: how requests are signed
+}
+
+// HMACConfig describes how a generic hmac channel's senders sign requests.
+// The signature covers the raw body or, with a timestamp header, the
+// timestamp, a dot and the body.
+type HMACConfig struct {
+	Algorithm       string        `yaml:"algorithm"`        // "sha1", "sha256" (default) or "sha512"
+	Header          string        `yaml:"header"`           // header carrying the signature; defaults to X-Signature
+	Prefix          string        `yaml:"prefix"`           // stripped from the header value, e.g. "sha256="
+	Encoding        string        `yaml:"encoding"`         // "hex" (default) or "base64"
+	Secrets         []string      `yaml:"secrets"`          // any of these may sign, so secrets can be rotated
+	TimestampHeader string        `yaml:"timestamp_header"` // optional header with the Unix time of signing
+	Tolerance       time.Duration `yaml:"tolerance"`        // how far the timestamp may be from now; defaults to 5m
+}
+
+// BasicAuthConfig is an HTTP basic auth credential.
+type BasicAuthConfig struct {
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+}
+
+// RateLimitConfig is a token bucket limiting how fast a channel accepts
+// webhooks. A zero rate disables it.
+type RateLimitConfig struct {
+	Rate  float64 `yaml:"rate"`   // sustained requests per second
+	Burst int     `yaml:"burst"`  // requests allowed at once; defaults to the rate rounded up
+	PerIP bool    `yaml:"per_ip"` // give each source IP its own bucket instead of sharing one
+}
+
+// HeaderConfig filters the request headers kept on a channel's events.
+// Names are case-insensitive and a trailing * matches any suffix. Common
+// secret headers are always redacted.
+type HeaderConfig struct {
+	Allow  []string `yaml:"allow"`  // keep only these headers; empty keeps all
+	Deny   []string `yaml:"deny"`   // drop these headers
+	Redact []string `yaml:"redact"` // keep these headers but replace their values
+}
+
+// RedactConfig removes sensitive values from a channel's event bodies.
+// Forwards send the stored copy, so store rules apply to the agent as well;
+// forward rules redact more for the agent only.
+type RedactConfig struct {
+	Store   RedactRules `yaml:"store"`
+	Forward RedactRules `yaml:"forward"`
+}
+
+// RedactRules select the body values replaced with "[REDACTED]".
+type RedactRules struct {
+	Paths    []string `yaml:"paths"`    // JSON paths such as alerts[*].labels.email; the whole value is replaced
+	Patterns []string `yaml:"patterns"` // regular expressions; each match within a string value is replaced
 }
 
 // SkillsConfig holds skill discovery settings.
@@ -43,10 +129,14 @@ type SkillsConfig struct {
 	Allowlist []string `yaml:"allowlist"`
 }
 
-// StoreConfig holds message/session store settings.
+// StoreConfig holds message/session store settings. The dead-letter queue
+// uses the same settings with its own backend and capacity.
 type StoreConfig struct {
-	Type     string `yaml:"type"`
-	Capacity int    `yaml:"capacity"`
+	Type        string        `yaml:"type"` // "memory", "sqlite" or "file"
+	Capacity    int           `yaml:"capacity"`
+	Path        string        `yaml:"path"`         // database file for sqlite, directory for file
+	SegmentSize int64         `yaml:"segment_size"` // file store: rotate segments at this many bytes
+	Retention   time.Duration `yaml:"retention"`    // file store: drop events older than this; 0 keeps all
 }
 
 // LoggingConfig holds structured logging settings.
@@ -55,6 +145,33 @@ type LoggingConfig struct {
 	Format string `yaml:"format"`
 }
 
+// TracingConfig controls OpenTelemetry tracing of the webhook pipeline.
+type TracingConfig struct {
+	Exporter    string            `yaml:"exporter"`     // "none", "otlp", "stdout" or "file"
+	Endpoint    string            `yaml:"endpoint"`     // otlp: collector host:port or URL for OTLP over HTTP
+	Insecure    bool              `yaml:"insecure"`     // otlp: use plain HTTP instead of HTTPS
+	Headers     map[string]string `yaml:"headers"`      // otlp: extra request headers, e.g. for auth
+	Path        string            `yaml:"path"`         // file: spans are appended here as JSON
+	ServiceName string            `yaml:"service_name"` // service.name resource attribute
+	SampleRatio *float64          `yaml:"sample_ratio"` // fraction of new traces sampled, 1 if unset; incoming sampled traces are always kept
+}
+
+// AdminConfig protects the admin API and can move it to its own listener.
+type AdminConfig struct {
+	Host     string           `yaml:"host"`      // admin listener host; defaults to server.host
+	Port     int              `yaml:"port"`      // admin listener port; 0 serves the admin API on the main listener
+	Keys     []AdminKeyConfig `yaml:"keys"`      // empty leaves the admin API open
+	AuditLog string           `yaml:"audit_log"` // file admin requests are appended to; empty logs them with the gateway logger
+}
+
+// AdminKeyConfig is one admin API credential, sent as a bearer token or in
+// the X-API-Key header.
+type AdminKeyConfig struct {
+	Name string `yaml:"name"` // identifies the caller in the audit log
+	Key  string `yaml:"key"`
+	Role string `yaml:"role"` // "viewer" (read-only) or "operator" (may also replay, requeue and discard)
+}
+
 // defaults applies sane defaults to zero-valued fields.
 func (c *Config) defaults() {
 	if c.Server.Host == "" {
@@ -63,21 +180,77 @@ func (c *Config) defaults() {
 	if c.Server.Port == 0 {
 		c.Server.Port = 8080
 	}
+	if c.Server.Workers == 0 {
+		c.Server.Workers = 4
+	}
+	if c.Server.QueueSize == 0 {
+		c.Server.QueueSize = 100
+	}
+	if c.Agent.Type == "" {
+		c.Agent.Type = "stub"
+	}
 	if c.Agent.Timeout == 0 {
 		c.Agent.Timeout = 30 * time.Second
 	}
-	if c.Store.Type == "" {
-		c.Store.Type = "memory"
+	if c.Agent.Retry.MaxAttempts == 0 {
+		c.Agent.Retry.MaxAttempts = 3
+	}
+	if c.Agent.Retry.BaseBackoff == 0 {
+		c.Agent.Retry.BaseBackoff = 200 * time.Millisecond
+	}
+	if c.Agent.Retry.MaxBackoff == 0 {
+		c.Agent.Retry.MaxBackoff = 5 * time.Second
+	}
+	if c.Agent.Breaker.FailureThreshold == 0 {
+		c.Agent.Breaker.FailureThreshold = 5
 	}
-	if c.Store.Capacity == 0 {
-		c.Store.Capacity = 1000
+	if c.Agent.Breaker.Cooldown == 0 {
+		c.Agent.Breaker.Cooldown = 30 * time.Second
 	}
+	if c.Agent.Breaker.HalfOpenRequests == 0 {
+		c.Agent.Breaker.HalfOpenRequests = 1
+	}
+	for i := range c.Channels {
+		if c.Channels[i].Mode == "" {
+			c.Channels[i].Mode = "sync"
+		}
+		if rl := &c.Channels[i].RateLimit; rl.Rate > 0 && rl.Burst == 0 {
+			rl.Burst = max(1, int(math.Ceil(rl.Rate)))
+		}
+		if c.Channels[i].Type == "hmac" {
+			c.Channels[i].HMAC.defaults()
+		}
+	}
+	c.Store.defaults()
+	c.DeadLetter.defaults()
 	if c.Logging.Level == "" {
 		c.Logging.Level = "info"
 	}
 	if c.Logging.Format == "" {
 		c.Logging.Format = "json"
 	}
+	if c.Tracing.Exporter == "" {
+		c.Tracing.Exporter = "none"
+	}
+	if c.Tracing.Exporter == "otlp" && c.Tracing.Endpoint == "" {
+		c.Tracing.Endpoint = "localhost:4318"
+	}
+	if c.Tracing.ServiceName == "" {
+		c.Tracing.ServiceName = "claude-pod"
+	}
+	if c.Tracing.SampleRatio == nil {
+		// Only an absent key defaults; 0 means sample no new traces.
+		ratio := 1.0
+		c.Tracing.SampleRatio = &ratio
+	}
+	if c.Admin.Port != 0 && c.Admin.Host == "" {
+		c.Admin.Host = c.Server.Host
+	}
+	for i := range c.Admin.Keys {
+		if c.Admin.Keys[i].Role == "" {
+			c.Admin.Keys[i].Role = "viewer"
+		}
+	}
 }
 
 // validate checks required fields and value constraints.
@@ -85,9 +258,45 @@ func (c *Config) validate() error {
 	if c.Server.Port < 1 || c.Server.Port > 65535 {
 		return fmt.Errorf("server.port must be between 1 and 65535, got %d", c.Server.Port)
 	}
+	if c.Server.Workers < 1 {
+		return fmt.Errorf("server.workers must be at least 1")
+	}
+	if c.Server.QueueSize < 1 {
+		return fmt.Errorf("server.queue_size must be at least 1")
+	}
 	if c.Agent.Timeout < 0 {
 		return fmt.Errorf("agent.timeout must be non-negative")
 	}
+	switch c.Agent.Type {
+	case "stub":
+	case "http":
+		if c.Agent.URL == "" {
+			return fmt.Errorf("agent.url is required when agent.type is http")
+		}
+	default:
+		return fmt.Errorf("agent.type must be stub or http, got %q", c.Agent.Type)
+	}
+	if c.Agent.Retry.MaxAttempts < 1 {
+		return fmt.Errorf("agent.retry.max_attempts must be at least 1")
+	}
+	if c.Agent.Retry.BaseBackoff < 0 || c.Agent.Retry.MaxBackoff < 0 {
+		return fmt.Errorf("agent.retry backoff durations must be non-negative")
+	}
+	if c.Agent.Retry.MaxBackoff < c.Agent.Retry.BaseBackoff {
+		return fmt.Errorf("agent.retry.max_backoff must not be less than base_backoff")
+	}
+	if c.Agent.Retry.Jitter < 0 || c.Agent.Retry.Jitter > 1 {
+		return fmt.Errorf("agent.retry.jitter must be between 0 and 1, got %g", c.Agent.Retry.Jitter)
+	}
+	if c.Agent.Breaker.FailureThreshold < 1 {
+		return fmt.Errorf("agent.breaker.failure_threshold must be at least 1")
+	}
+	if c.Agent.Breaker.Cooldown < 0 {
+		return fmt.Errorf("agent.breaker.cooldown must be non-negative")
+	}
+	if c.Agent.Breaker.HalfOpenRequests < 1 {
+		return fmt.Errorf("agent.breaker.half_open_requests must be at least 1")
+	}
 	for i, ch := range c.Channels {
 		if ch.Name == "" {
 			return fmt.Errorf("channels[%d].name is required", i)
@@ -95,9 +304,187 @@ func (c *Config) validate() error {
 		if ch.Type == "" {
 			return fmt.Errorf("channels[%d].type is required", i)
 		}
+		if ch.Mode != "sync" && ch.Mode != "async" {
+			return fmt.Errorf("channels[%d].mode must be sync or async, got %q", i, ch.Mode)
+		}
+		if ch.RateLimit.Rate < 0 || ch.RateLimit.Burst < 0 {
+			return fmt.Errorf("channels[%d].rate_limit rate and burst must be non-negative", i)
+		}
+		if ch.RateLimit.Rate == 0 && (ch.RateLimit.Burst > 0 || ch.RateLimit.PerIP) {
+			return fmt.Errorf("channels[%d].rate_limit.rate is required when burst or per_ip is set", i)
+		}
+		if (ch.Type == "github" || ch.Type == "slack") && ch.Secret == "" {
+			return fmt.Errorf("channels[%d].secret is required when type is %s", i, ch.Type)
+		}
+		if (ch.BasicAuth.Username == "") != (ch.BasicAuth.Password == "") {
+			return fmt.Errorf("channels[%d].basic_auth needs both username and password", i)
+		}
+		if ch.Type == "hmac" {
+			if err := ch.HMAC.validate(fmt.Sprintf("channels[%d].hmac", i)); err != nil {
+				return err
+			}
+		}
+		if err := ch.Headers.validate(fmt.Sprintf("channels[%d].headers", i)); err != nil {
+			return err
+		}
+		if err := ch.Redact.Store.validate(fmt.Sprintf("channels[%d].redact.store", i)); err != nil {
+			return err
+		}
+		if err := ch.Redact.Forward.validate(fmt.Sprintf("channels[%d].redact.forward", i)); err != nil {
+			return err
+		}
+	}
+	if err := c.Store.validate("store"); err != nil {
+		return err
+	}
+	if err := c.DeadLetter.validate("dead_letter"); err != nil {
+		return err
+	}
+	if c.DeadLetter.Type != "memory" && c.DeadLetter.Type == c.Store.Type && c.DeadLetter.Path == c.Store.Path {
+		return fmt.Errorf("dead_letter.path must differ from store.path")
+	}
+	switch c.Tracing.Exporter {
+	case "none", "otlp", "stdout":
+	case "file":
+		if c.Tracing.Path == "" {
+			return fmt.Errorf("tracing.path is required when tracing.exporter is file")
+		}
+	default:
+		return fmt.Errorf("tracing.exporter must be none, otlp, stdout or file, got %q", c.Tracing.Exporter)
+	}
+	if r := *c.Tracing.SampleRatio; r < 0 || r > 1 {
+		return fmt.Errorf("tracing.sample_ratio must be between 0 and 1, got %g", r)
+	}
+	if c.Admin.Port < 0 || c.Admin.Port > 65535 {
+		return fmt.Errorf("admin.port must be between 0 and 65535, got %d", c.Admin.Port)
+	}
+	if c.Admin.Port == c.Server.Port {
+		return fmt.Errorf("admin.port must differ from server.port")
+	}
+	names := make(map[string]bool, len(c.Admin.Keys))
+	for i, k := range c.Admin.Keys {
+		if k.Name == "" {
+			return fmt.Errorf("admin.keys[%d].name is required", i)
+		}
+		if names[k.Name] {
+			return fmt.Errorf("admin.keys[%d].name %q is used more than once", i, k.Name)
+		}
+		names[k.Name] = true
+		if k.Key == "" {
+			return fmt.Errorf("admin.keys[%d].key is required", i)
+		}
+		if k.Role != "viewer" && k.Role != "operator" {
+			return fmt.Errorf("admin.keys[%d].role must be viewer or operator, got %q", i, k.Role)
+		}
+	}
+	return nil
+}
+
+// defaults applies defaults shared by the event store and dead-letter queue.
+func (s *StoreConfig) defaults() {
+	if s.Type == "" {
+		s.Type = "memory"
 	}
-	if c.Store.Capacity < 0 {
-		return fmt.Errorf("store.capacity must be non-negative")
+	if s.Capacity == 0 {
+		s.Capacity = 1000
+	}
+}
+
+// validate checks a store section; field names it in error messages.
+func (s *StoreConfig) validate(field string) error {
+	if s.Capacity < 0 {
+		return fmt.Errorf("%s.capacity must be non-negative", field)
+	}
+	if s.Type == "sqlite" && s.Path == "" {
+		return fmt.Errorf("%s.path is required when %s.type is sqlite", field, field)
+	}
+	if s.Type == "file" && s.Path == "" {
+		return fmt.Errorf("%s.path is required when %s.type is file", field, field)
+	}
+	if s.SegmentSize < 0 {
+		return fmt.Errorf("%s.segment_size must be non-negative", field)
+	}
+	if s.Retention < 0 {
+		return fmt.Errorf("%s.retention must be non-negative", field)
+	}
+	return nil
+}
+
+func (h *HMACConfig) defaults() {
+	if h.Algorithm == "" {
+		h.Algorithm = "sha256"
+	}
+	if h.Header == "" {
+		h.Header = "X-Signature"
+	}
+	if h.Encoding == "" {
+		h.Encoding = "hex"
+	}
+	if h.TimestampHeader != "" && h.Tolerance == 0 {
+		h.Tolerance = 5 * time.Minute
+	}
+}
+
+// validate checks the signature scheme; field names the section in error messages.
+func (h *HMACConfig) validate(field string) error {
+	switch h.Algorithm {
+	case "sha1", "sha256", "sha512":
+	default:
+		return fmt.Errorf("%s.algorithm must be sha1, sha256 or sha512, got %q", field, h.Algorithm)
+	}
+	switch h.Encoding {
+	case "hex", "base64":
+	default:
+		return fmt.Errorf("%s.encoding must be hex or base64, got %q", field, h.Encoding)
+	}
+	if len(h.Secrets) == 0 {
+		return fmt.Errorf("%s.secrets must list at least one secret", field)
+	}
+	for j, secret := range h.Secrets {
+		if secret == "" {
+			return fmt.Errorf("%s.secrets[%d] must not be empty", field, j)
+		}
+	}
+	if h.Tolerance < 0 {
+		return fmt.Errorf("%s.tolerance must be non-negative", field)
+	}
+	return nil
+}
+
+// validate checks header name patterns; field names the section in error messages.
+func (h *HeaderConfig) validate(field string) error {
+	lists := []struct {
+		name  string
+		names []string
+	}{{"allow", h.Allow}, {"deny", h.Deny}, {"redact", h.Redact}}
+	for _, l := range lists {
+		for j, name := range l.names {
+			if name == "" || name == "*" {
+				return fmt.Errorf("%s.%s[%d] must name a header", field, l.name, j)
+			}
+			if strings.Contains(strings.TrimSuffix(name, "*"), "*") {
+				return fmt.Errorf("%s.%s[%d] may only use * at the end, got %q", field, l.name, j, name)
+			}
+		}
+	}
+	return nil
+}
+
+// validate checks that paths are given and patterns compile; field names
+// the section in error messages.
+func (r *RedactRules) validate(field string) error {
+	for j, path := range r.Paths {
+		if strings.TrimLeft(path, "$.") == "" {
+			return fmt.Errorf("%s.paths[%d] must not be empty", field, j)
+		}
+	}
+	for j, pattern := range r.Patterns {
+		if pattern == "" {
+			return fmt.Errorf("%s.patterns[%d] must not be empty", field, j)
+		}
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("%s.patterns[%d]: %w", field, j, err)
+		}
 	}
 	return nil
 }
@@ -106,8 +493,23 @@ func (c *Config) validate() error {
 // environment variable values. This allows keeping secrets out of YAML.
 func (c *Config) expandEnv() {
 	c.Agent.URL = os.ExpandEnv(c.Agent.URL)
+	c.Store.Path = os.ExpandEnv(c.Store.Path)
+	c.DeadLetter.Path = os.ExpandEnv(c.DeadLetter.Path)
+	c.Tracing.Endpoint = os.ExpandEnv(c.Tracing.Endpoint)
+	for k, v := range c.Tracing.Headers {
+		c.Tracing.Headers[k] = os.ExpandEnv(v)
+	}
 	for i := range c.Channels {
 		c.Channels[i].Auth = os.ExpandEnv(c.Channels[i].Auth)
+		c.Channels[i].BasicAuth.Password = os.ExpandEnv(c.Channels[i].BasicAuth.Password)
+		c.Channels[i].Secret = os.ExpandEnv(c.Channels[i].Secret)
+		for j, secret := range c.Channels[i].HMAC.Secrets {
+			c.Channels[i].HMAC.Secrets[j] = os.ExpandEnv(secret)
+		}
+	}
+	c.Admin.AuditLog = os.ExpandEnv(c.Admin.AuditLog)
+	for i := range c.Admin.Keys {
+		c.Admin.Keys[i].Key = os.ExpandEnv(c.Admin.Keys[i].Key)
 	}
 }
 
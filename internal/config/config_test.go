@@ -116,15 +116,48 @@ func TestLoad_Defaults(t *testing.T) {
 	if cfg.Server.Port != 8080 {
 		t.Errorf("default server.port = %d, want %d", cfg.Server.Port, 8080)
 	}
+	if cfg.Server.Workers != 4 {
+		t.Errorf("default server.workers = %d, want %d", cfg.Server.Workers, 4)
+	}
+	if cfg.Server.QueueSize != 100 {
+		t.Errorf("default server.queue_size = %d, want %d", cfg.Server.QueueSize, 100)
+	}
+	if cfg.Agent.Type != "stub" {
+		t.Errorf("default agent.type = %q, want %q", cfg.Agent.Type, "stub")
+	}
 	if cfg.Agent.Timeout != 30*time.Second {
 		t.Errorf("default agent.timeout = %v, want %v", cfg.Agent.Timeout, 30*time.Second)
 	}
+	if cfg.Agent.Retry.MaxAttempts != 3 {
+		t.Errorf("default agent.retry.max_attempts = %d, want %d", cfg.Agent.Retry.MaxAttempts, 3)
+	}
+	if cfg.Agent.Retry.BaseBackoff != 200*time.Millisecond {
+		t.Errorf("default agent.retry.base_backoff = %v, want %v", cfg.Agent.Retry.BaseBackoff, 200*time.Millisecond)
+	}
+	if cfg.Agent.Retry.MaxBackoff != 5*time.Second {
+		t.Errorf("default agent.retry.max_backoff = %v, want %v", cfg.Agent.Retry.MaxBackoff, 5*time.Second)
+	}
+	if cfg.Agent.Breaker.FailureThreshold != 5 {
+		t.Errorf("default agent.breaker.failure_threshold = %d, want %d", cfg.Agent.Breaker.FailureThreshold, 5)
+	}
+	if cfg.Agent.Breaker.Cooldown != 30*time.Second {
+		t.Errorf("default agent.breaker.cooldown = %v, want %v", cfg.Agent.Breaker.Cooldown, 30*time.Second)
+	}
+	if cfg.Agent.Breaker.HalfOpenRequests != 1 {
+		t.Errorf("default agent.breaker.half_open_requests = %d, want %d", cfg.Agent.Breaker.HalfOpenRequests, 1)
+	}
 	if cfg.Store.Type != "memory" {
 		t.Errorf("default store.type = %q, want %q", cfg.Store.Type, "memory")
 	}
 	if cfg.Store.Capacity != 1000 {
 		t.Errorf("default store.capacity = %d, want %d", cfg.Store.Capacity, 1000)
 	}
+	if cfg.DeadLetter.Type != "memory" {
+		t.Errorf("default dead_letter.type = %q, want %q", cfg.DeadLetter.Type, "memory")
+	}
+	if cfg.DeadLetter.Capacity != 1000 {
+		t.Errorf("default dead_letter.capacity = %d, want %d", cfg.DeadLetter.Capacity, 1000)
+	}
 	if cfg.Logging.Level != "info" {
 		t.Errorf("default logging.level = %q, want %q", cfg.Logging.Level, "info")
 	}
@@ -158,6 +191,492 @@ server:
 	}
 }
 
+func TestLoad_AgentHTTP(t *testing.T) {
+	yaml := `
+agent:
+  type: http
+  url: "http://agent:3000/events"
+`
+	cfg, err := Load(writeTemp(t, yaml))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Agent.Type != "http" {
+		t.Errorf("agent.type = %q, want %q", cfg.Agent.Type, "http")
+	}
+}
+
+func TestLoad_ValidationError_AgentHTTPMissingURL(t *testing.T) {
+	yaml := `
+agent:
+  type: http
+`
+	_, err := Load(writeTemp(t, yaml))
+	if err == nil {
+		t.Fatal("expected validation error for http agent without url, got nil")
+	}
+}
+
+func TestLoad_ValidationError_AgentUnknownType(t *testing.T) {
+	yaml := `
+agent:
+  type: grpc
+`
+	_, err := Load(writeTemp(t, yaml))
+	if err == nil {
+		t.Fatal("expected validation error for unknown agent type, got nil")
+	}
+}
+
+func TestLoad_AgentRetry(t *testing.T) {
+	yaml := `
+agent:
+  retry:
+    max_attempts: 5
+    base_backoff: 100ms
+    max_backoff: 2s
+    jitter: 0.5
+`
+	cfg, err := Load(writeTemp(t, yaml))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	r := cfg.Agent.Retry
+	if r.MaxAttempts != 5 || r.BaseBackoff != 100*time.Millisecond || r.MaxBackoff != 2*time.Second || r.Jitter != 0.5 {
+		t.Errorf("agent.retry = %+v, want {5 100ms 2s 0.5}", r)
+	}
+}
+
+func TestLoad_ValidationError_AgentResilience(t *testing.T) {
+	cases := map[string]string{
+		"negative attempts":   "agent:\n  retry:\n    max_attempts: -1\n",
+		"max below base":      "agent:\n  retry:\n    base_backoff: 2s\n    max_backoff: 1s\n",
+		"jitter out of range": "agent:\n  retry:\n    jitter: 1.5\n",
+		"negative threshold":  "agent:\n  breaker:\n    failure_threshold: -1\n",
+		"negative cooldown":   "agent:\n  breaker:\n    cooldown: -1s\n",
+	}
+	for name, yaml := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Load(writeTemp(t, yaml)); err == nil {
+				t.Fatal("expected validation error, got nil")
+			}
+		})
+	}
+}
+
+func TestLoad_ChannelMode(t *testing.T) {
+	yaml := `
+channels:
+  - name: grafana
+    type: grafana
+    mode: async
+  - name: dummy
+    type: dummy
+`
+	cfg, err := Load(writeTemp(t, yaml))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Channels[0].Mode != "async" {
+		t.Errorf("channels[0].mode = %q, want %q", cfg.Channels[0].Mode, "async")
+	}
+	if cfg.Channels[1].Mode != "sync" {
+		t.Errorf("default channels[1].mode = %q, want %q", cfg.Channels[1].Mode, "sync")
+	}
+}
+
+func TestLoad_ValidationError_ChannelBadMode(t *testing.T) {
+	yaml := `
+channels:
+  - name: grafana
+    type: grafana
+    mode: later
+`
+	_, err := Load(writeTemp(t, yaml))
+	if err == nil {
+		t.Fatal("expected validation error for unknown channel mode, got nil")
+	}
+}
+
+func TestLoad_ChannelRateLimit(t *testing.T) {
+	yaml := `
+channels:
+  - name: grafana
+    type: grafana
+    rate_limit:
+      rate: 2.5
+      per_ip: true
+  - name: github
+    type: github
+    secret: s3cret
+    rate_limit:
+      rate: 10
+      burst: 50
+`
+	cfg, err := Load(writeTemp(t, yaml))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	rl := cfg.Channels[0].RateLimit
+	if rl.Rate != 2.5 || rl.Burst != 3 || !rl.PerIP {
+		t.Errorf("channels[0].rate_limit = %+v, want rate 2.5, default burst 3, per_ip", rl)
+	}
+	if rl := cfg.Channels[1].RateLimit; rl.Burst != 50 || rl.PerIP {
+		t.Errorf("channels[1].rate_limit = %+v, want burst 50", rl)
+	}
+}
+
+func TestLoad_ValidationError_ChannelRateLimit(t *testing.T) {
+	cases := map[string]string{
+		"negative rate":  "rate: -1",
+		"negative burst": "{rate: 1, burst: -1}",
+		"burst no rate":  "burst: 5",
+		"per_ip no rate": "per_ip: true",
+	}
+	for name, limit := range cases {
+		t.Run(name, func(t *testing.T) {
+			yaml := `
+channels:
+  - name: grafana
+    type: grafana
+    rate_limit: ` + limit + "\n"
+			if _, err := Load(writeTemp(t, yaml)); err == nil {
+				t.Fatal("expected validation error, got nil")
+			}
+		})
+	}
+}
+
+func TestLoad_ValidationError_ChannelHeaders(t *testing.T) {
+	cases := map[string]string{
+		"empty name":     "allow: ['']",
+		"bare wildcard":  "deny: ['*']",
+		"inner wildcard": "redact: ['X-*-Token']",
+	}
+	for name, headers := range cases {
+		t.Run(name, func(t *testing.T) {
+			yaml := `
+channels:
+  - name: grafana
+    type: grafana
+    headers: {` + headers + "}\n"
+			if _, err := Load(writeTemp(t, yaml)); err == nil {
+				t.Fatal("expected validation error, got nil")
+			}
+		})
+	}
+}
+
+func TestLoad_ValidationError_ChannelRedact(t *testing.T) {
+	cases := map[string]string{
+		"empty path":    "store: {paths: ['$.']}",
+		"empty pattern": "forward: {patterns: ['']}",
+		"bad pattern":   "forward: {patterns: ['(unclosed']}",
+	}
+	for name, redact := range cases {
+		t.Run(name, func(t *testing.T) {
+			yaml := `
+channels:
+  - name: grafana
+    type: grafana
+    redact: {` + redact + "}\n"
+			if _, err := Load(writeTemp(t, yaml)); err == nil {
+				t.Fatal("expected validation error, got nil")
+			}
+		})
+	}
+}
+
+func TestLoad_ChannelBasicAuth(t *testing.T) {
+	t.Setenv("TEST_AM_PASSWORD", "pw")
+	yaml := `
+channels:
+  - name: am
+    type: alertmanager
+    basic_auth:
+      username: alertmanager
+      password: ${TEST_AM_PASSWORD}
+`
+	cfg, err := Load(writeTemp(t, yaml))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ba := cfg.Channels[0].BasicAuth; ba.Username != "alertmanager" || ba.Password != "pw" {
+		t.Errorf("basic_auth = %+v, want expanded password", ba)
+	}
+
+	_, err = Load(writeTemp(t, "channels:\n  - {name: am, type: alertmanager, basic_auth: {username: alertmanager}}\n"))
+	if err == nil {
+		t.Fatal("expected validation error for basic_auth without password, got nil")
+	}
+}
+
+func TestLoad_ChannelHMAC(t *testing.T) {
+	t.Setenv("TEST_HMAC_SECRET", "s3cret")
+	yaml := `
+channels:
+  - name: hooks
+    type: hmac
+    hmac:
+      secrets: ["${TEST_HMAC_SECRET}", old]
+      timestamp_header: X-Timestamp
+  - name: plain
+    type: dummy
+`
+	cfg, err := Load(writeTemp(t, yaml))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	h := cfg.Channels[0].HMAC
+	if h.Algorithm != "sha256" || h.Header != "X-Signature" || h.Encoding != "hex" || h.Tolerance != 5*time.Minute {
+		t.Errorf("hmac = %+v, want defaults sha256, X-Signature, hex and 5m", h)
+	}
+	if h.Secrets[0] != "s3cret" || h.Secrets[1] != "old" {
+		t.Errorf("hmac.secrets = %v, want the first expanded", h.Secrets)
+	}
+	if cfg.Channels[1].HMAC.Algorithm != "" {
+		t.Error("hmac defaults applied to a channel of another type")
+	}
+}
+
+func TestLoad_ValidationError_ChannelHMAC(t *testing.T) {
+	cases := map[string]string{
+		"no secrets":         "{}",
+		"empty secret":       "{secrets: ['']}",
+		"unknown algorithm":  "{secrets: [s], algorithm: md5}",
+		"unknown encoding":   "{secrets: [s], encoding: base32}",
+		"negative tolerance": "{secrets: [s], timestamp_header: X-Timestamp, tolerance: -1s}",
+	}
+	for name, hmac := range cases {
+		t.Run(name, func(t *testing.T) {
+			yaml := `
+channels:
+  - name: hooks
+    type: hmac
+    hmac: ` + hmac + "\n"
+			if _, err := Load(writeTemp(t, yaml)); err == nil {
+				t.Fatal("expected validation error, got nil")
+			}
+		})
+	}
+}
+
+func TestLoad_ChannelSecret(t *testing.T) {
+	t.Setenv("TEST_GITHUB_SECRET", "s3cret")
+	yaml := `
+channels:
+  - name: gh
+    type: github
+    secret: ${TEST_GITHUB_SECRET}
+    events: [pull_request, workflow_run]
+`
+	cfg, err := Load(writeTemp(t, yaml))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ch := cfg.Channels[0]; ch.Secret != "s3cret" || len(ch.Events) != 2 {
+		t.Errorf("channel = %+v, want expanded secret and two events", ch)
+	}
+
+	for _, typ := range []string{"github", "slack"} {
+		if _, err := Load(writeTemp(t, "channels:\n  - {name: ch, type: "+typ+"}\n")); err == nil {
+			t.Errorf("expected validation error for %s channel without secret, got nil", typ)
+		}
+	}
+}
+
+func TestLoad_StoreSQLite(t *testing.T) {
+	yaml := `
+store:
+  type: sqlite
+  path: /var/lib/gateway/events.db
+`
+	cfg, err := Load(writeTemp(t, yaml))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Store.Path != "/var/lib/gateway/events.db" {
+		t.Errorf("store.path = %q, want %q", cfg.Store.Path, "/var/lib/gateway/events.db")
+	}
+}
+
+func TestLoad_ValidationError_StoreSQLiteMissingPath(t *testing.T) {
+	yaml := `
+store:
+  type: sqlite
+`
+	_, err := Load(writeTemp(t, yaml))
+	if err == nil {
+		t.Fatal("expected validation error for sqlite store without path, got nil")
+	}
+}
+
+func TestLoad_StoreFile(t *testing.T) {
+	yaml := `
+store:
+  type: file
+  path: /var/lib/gateway/events
+  segment_size: 1048576
+  retention: 168h
+`
+	cfg, err := Load(writeTemp(t, yaml))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Store.SegmentSize != 1<<20 {
+		t.Errorf("store.segment_size = %d, want %d", cfg.Store.SegmentSize, 1<<20)
+	}
+	if cfg.Store.Retention != 168*time.Hour {
+		t.Errorf("store.retention = %v, want 168h", cfg.Store.Retention)
+	}
+}
+
+func TestLoad_ValidationError_StoreFileMissingPath(t *testing.T) {
+	yaml := `
+store:
+  type: file
+`
+	_, err := Load(writeTemp(t, yaml))
+	if err == nil {
+		t.Fatal("expected validation error for file store without path, got nil")
+	}
+}
+
+func TestLoad_DeadLetter(t *testing.T) {
+	yaml := `
+store:
+  type: sqlite
+  path: /var/lib/gateway/events.db
+  capacity: 10000
+dead_letter:
+  type: sqlite
+  path: /var/lib/gateway/dead.db
+  capacity: 50
+`
+	cfg, err := Load(writeTemp(t, yaml))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.DeadLetter.Capacity != 50 || cfg.DeadLetter.Path != "/var/lib/gateway/dead.db" {
+		t.Errorf("dead_letter = %+v, want sqlite at dead.db with capacity 50", cfg.DeadLetter)
+	}
+}
+
+func TestLoad_ValidationError_DeadLetter(t *testing.T) {
+	tests := map[string]string{
+		"missing path": `
+dead_letter:
+  type: file
+`,
+		"negative capacity": `
+dead_letter:
+  capacity: -1
+`,
+		"shared path": `
+store:
+  type: sqlite
+  path: /var/lib/gateway/events.db
+dead_letter:
+  type: sqlite
+  path: /var/lib/gateway/events.db
+`,
+	}
+	for name, yaml := range tests {
+		if _, err := Load(writeTemp(t, yaml)); err == nil {
+			t.Errorf("%s: expected validation error, got nil", name)
+		}
+	}
+}
+
+func TestLoad_Tracing(t *testing.T) {
+	cfg, err := Load(writeTemp(t, "tracing:\n  exporter: otlp\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tr := cfg.Tracing
+	if tr.Endpoint != "localhost:4318" || tr.ServiceName != "claude-pod" || tr.SampleRatio == nil || *tr.SampleRatio != 1 {
+		t.Errorf("tracing defaults = %+v", tr)
+	}
+
+	// An explicit 0 samples no new traces rather than falling back to 1.
+	cfg, err = Load(writeTemp(t, "tracing:\n  exporter: stdout\n  sample_ratio: 0\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if r := cfg.Tracing.SampleRatio; r == nil || *r != 0 {
+		t.Errorf("sample_ratio = %v, want 0 kept", r)
+	}
+
+	cfg, err = Load(writeTemp(t, "server:\n  port: 9090\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Tracing.Exporter != "none" {
+		t.Errorf("default exporter = %q, want none", cfg.Tracing.Exporter)
+	}
+}
+
+func TestLoad_ValidationError_Tracing(t *testing.T) {
+	tests := map[string]string{
+		"unknown exporter":     "tracing:\n  exporter: jaeger\n",
+		"file without path":    "tracing:\n  exporter: file\n",
+		"sample ratio above 1": "tracing:\n  exporter: stdout\n  sample_ratio: 1.5\n",
+	}
+	for name, yaml := range tests {
+		if _, err := Load(writeTemp(t, yaml)); err == nil {
+			t.Errorf("%s: expected validation error, got nil", name)
+		}
+	}
+}
+
+func TestLoad_Admin(t *testing.T) {
+	t.Setenv("TEST_ADMIN_KEY", "from-env")
+	yaml := `
+server:
+  host: 127.0.0.1
+admin:
+  port: 9091
+  audit_log: /var/log/gateway-audit.log
+  keys:
+    - name: dashboard
+      key: ${TEST_ADMIN_KEY}
+    - name: oncall
+      key: plain
+      role: operator
+`
+	cfg, err := Load(writeTemp(t, yaml))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Admin.Host != "127.0.0.1" || cfg.Admin.Port != 9091 {
+		t.Errorf("admin listener = %s:%d, want server host and port 9091", cfg.Admin.Host, cfg.Admin.Port)
+	}
+	if k := cfg.Admin.Keys[0]; k.Key != "from-env" || k.Role != "viewer" {
+		t.Errorf("admin.keys[0] = %+v, want expanded key and default viewer role", k)
+	}
+	if cfg.Admin.Keys[1].Role != "operator" {
+		t.Errorf("admin.keys[1].role = %q, want operator", cfg.Admin.Keys[1].Role)
+	}
+}
+
+func TestLoad_ValidationError_Admin(t *testing.T) {
+	cases := map[string]string{
+		"port out of range": "admin:\n  port: 70000\n",
+		"port clash":        "server:\n  port: 8080\nadmin:\n  port: 8080\n",
+		"missing name":      "admin:\n  keys:\n    - key: k\n",
+		"duplicate name":    "admin:\n  keys:\n    - {name: a, key: k1}\n    - {name: a, key: k2}\n",
+		"missing key":       "admin:\n  keys:\n    - name: a\n",
+		"unknown role":      "admin:\n  keys:\n    - {name: a, key: k, role: root}\n",
+	}
+	for name, yaml := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Load(writeTemp(t, yaml)); err == nil {
+				t.Fatal("expected validation error, got nil")
+			}
+		})
+	}
+}
+
 func TestLoad_ValidationError_ChannelMissingName(t *testing.T) {
 	yaml := `
 channels:
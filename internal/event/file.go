@@ -0,0 +1,455 @@
+package event
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/youmna-rabie/claude-pod/internal/types"
+)
+
+const (
+	segmentPrefix = "segment-"
+	segmentSuffix = ".jsonl"
+
+	// DefaultSegmentSize is the size at which the active segment is rotated.
+	DefaultSegmentSize = 8 << 20 // 8 MB
+)
+
+// FileOptions configures a FileStore.
+type FileOptions struct {
+	Capacity    int           // maximum events kept, as for MemoryStore
+	SegmentSize int64         // rotate the active segment once it reaches this size
+	Retention   time.Duration // drop events older than this on compaction; 0 keeps all
+	Logger      *slog.Logger  // reports records skipped on replay; nil uses slog.Default
+}
+
+// fileRecord is one line of a segment file.
+type fileRecord struct {
+	Op       string               `json:"op"` // "save", "transition", "status" or "attempts"
+	Event    *types.Event         `json:"event,omitempty"`
+	History  []types.StatusChange `json:"history,omitempty"` // full history, in compacted saves
+	ID       uuid.UUID            `json:"id,omitempty"`
+	Change   *types.StatusChange  `json:"change,omitempty"`
+	Status   types.EventStatus    `json:"status,omitempty"`
+	Attempts int                  `json:"attempts,omitempty"`
+}
+
+// FileStore is a persistent event store that appends every change to JSONL
+// segment files in a directory. Reads are served from an in-memory index
+// (a MemoryStore) that is rebuilt on startup by replaying the segments.
+//
+// When the active segment reaches SegmentSize it is rotated, and all closed
+// segments are compacted into one holding a single record per live event,
+// dropping events that are evicted by capacity or older than Retention.
+type FileStore struct {
+	mu     sync.RWMutex
+	dir    string
+	opts   FileOptions
+	index  *MemoryStore
+	active *os.File
+	seq    int   // number of the active segment
+	size   int64 // bytes written to the active segment
+}
+
+// NewFileStore opens (or creates) a file store in dir and replays its segments.
+func NewFileStore(dir string, opts FileOptions) (*FileStore, error) {
+	if opts.Capacity <= 0 {
+		return nil, ErrInvalidCapacity
+	}
+	if opts.SegmentSize <= 0 {
+		opts.SegmentSize = DefaultSegmentSize
+	}
+	if opts.Logger == nil {
+		opts.Logger = slog.Default()
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("creating store dir %s: %w", dir, err)
+	}
+
+	index, err := NewMemoryStore(opts.Capacity)
+	if err != nil {
+		return nil, err
+	}
+	s := &FileStore{dir: dir, opts: opts, index: index}
+
+	segments, err := s.segments()
+	if err != nil {
+		return nil, err
+	}
+	for _, seq := range segments {
+		if err := s.replay(seq); err != nil {
+			return nil, err
+		}
+	}
+
+	s.seq = 1
+	if len(segments) > 0 {
+		s.seq = segments[len(segments)-1]
+	}
+	if err := s.openActive(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Close closes the active segment.
+func (s *FileStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.active.Close()
+}
+
+// Save appends the event to the active segment and indexes it.
+func (s *FileStore) Save(event types.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.append(fileRecord{Op: "save", Event: &event}); err != nil {
+		return err
+	}
+	if err := s.index.Save(event); err != nil {
+		return err
+	}
+	return s.maybeRotate()
+}
+
+// Get retrieves an event by ID.
+func (s *FileStore) Get(id uuid.UUID) (types.Event, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.index.Get(id)
+}
+
+// List returns up to limit events ordered newest-first, skipping the first offset results.
+func (s *FileStore) List(limit, offset int) ([]types.Event, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.index.List(limit, offset)
+}
+
+// Query returns one page of events matching q, newest-first.
+func (s *FileStore) Query(q Query) (Page, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.index.Query(q)
+}
+
+// UpdateStatus appends a status change and applies it to the index.
+func (s *FileStore) UpdateStatus(id uuid.UUID, status types.EventStatus) error {
+	return s.Transition(id, types.StatusChange{Status: status})
+}
+
+// Transition appends a status change and applies it to the index.
+func (s *FileStore) Transition(id uuid.UUID, change types.StatusChange) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.index.Get(id); err != nil {
+		return err
+	}
+	// Stamp the change before writing so replay reproduces the same history.
+	if change.Timestamp.IsZero() {
+		change.Timestamp = time.Now()
+	}
+	if err := s.append(fileRecord{Op: "transition", ID: id, Change: &change}); err != nil {
+		return err
+	}
+	if err := s.index.Transition(id, change); err != nil {
+		return err
+	}
+	return s.maybeRotate()
+}
+
+// History returns an event's status changes, oldest first.
+func (s *FileStore) History(id uuid.UUID) ([]types.StatusChange, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.index.History(id)
+}
+
+// UpdateAttempts appends an attempt count change and applies it to the index.
+func (s *FileStore) UpdateAttempts(id uuid.UUID, attempts int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.index.Get(id); err != nil {
+		return err
+	}
+	if err := s.append(fileRecord{Op: "attempts", ID: id, Attempts: attempts}); err != nil {
+		return err
+	}
+	if err := s.index.UpdateAttempts(id, attempts); err != nil {
+		return err
+	}
+	return s.maybeRotate()
+}
+
+// Delete appends a deletion and removes the event from the index.
+func (s *FileStore) Delete(id uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.index.Get(id); err != nil {
+		return err
+	}
+	if err := s.append(fileRecord{Op: "delete", ID: id}); err != nil {
+		return err
+	}
+	if err := s.index.Delete(id); err != nil {
+		return err
+	}
+	return s.maybeRotate()
+}
+
+// Count returns the number of events currently stored.
+func (s *FileStore) Count() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.index.Count()
+}
+
+// append writes a record to the active segment. Callers must hold s.mu.
+func (s *FileStore) append(rec fileRecord) error {
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("encoding record: %w", err)
+	}
+	line = append(line, '\n')
+
+	n, err := s.active.Write(line)
+	s.size += int64(n)
+	if err != nil {
+		return fmt.Errorf("writing segment: %w", err)
+	}
+	return nil
+}
+
+// maybeRotate closes the active segment once it is full, starts a new one,
+// and compacts the closed segments. It runs after the index has been updated
+// so the compacted snapshot includes the latest change. Callers must hold s.mu.
+func (s *FileStore) maybeRotate() error {
+	if s.size < s.opts.SegmentSize {
+		return nil
+	}
+	if err := s.active.Close(); err != nil {
+		return fmt.Errorf("closing segment: %w", err)
+	}
+	closed := s.seq
+	s.seq++
+	if err := s.openActive(); err != nil {
+		return err
+	}
+	return s.compact(closed)
+}
+
+// compact rewrites segments up to and including upTo as a single segment
+// numbered upTo containing one save record per retained event, then removes
+// the older segments. Callers must hold s.mu.
+//
+// Each snapshot record carries the event's full history. Should an event
+// also appear in a later segment, replay treats the repeated save as an
+// overwrite, so this is harmless.
+func (s *FileStore) compact(upTo int) error {
+	cutoff := time.Time{}
+	if s.opts.Retention > 0 {
+		cutoff = time.Now().Add(-s.opts.Retention)
+	}
+
+	// Rebuild the index from the retained events so reads match what a
+	// restart would see. Sequences are kept so query cursors survive.
+	index, err := NewMemoryStore(s.opts.Capacity)
+	if err != nil {
+		return err
+	}
+
+	tmp := s.segmentPath(upTo) + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return fmt.Errorf("creating compacted segment: %w", err)
+	}
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+
+	// Write oldest-first to preserve insertion order.
+	var encErr error
+	s.index.each(func(seq uint64, ev types.Event, history []types.StatusChange) {
+		if encErr != nil || ev.Timestamp.Before(cutoff) {
+			return
+		}
+		encErr = enc.Encode(fileRecord{Op: "save", Event: &ev, History: history})
+		index.restore(ev, seq, history)
+	})
+	if encErr != nil {
+		f.Close()
+		os.Remove(tmp)
+		return fmt.Errorf("writing compacted segment: %w", encErr)
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return fmt.Errorf("writing compacted segment: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("writing compacted segment: %w", err)
+	}
+	if err := os.Rename(tmp, s.segmentPath(upTo)); err != nil {
+		return fmt.Errorf("replacing segment: %w", err)
+	}
+
+	segments, err := s.segments()
+	if err != nil {
+		return err
+	}
+	for _, seq := range segments {
+		if seq < upTo {
+			if err := os.Remove(s.segmentPath(seq)); err != nil {
+				return fmt.Errorf("removing compacted segment: %w", err)
+			}
+		}
+	}
+
+	s.index = index
+	return nil
+}
+
+// replay applies every record in a segment to the index. Lines that cannot be
+// decoded, such as a torn final write after a crash, are skipped and counted
+// in a warning.
+func (s *FileStore) replay(seq int) error {
+	f, err := os.Open(s.segmentPath(seq))
+	if err != nil {
+		return fmt.Errorf("opening segment: %w", err)
+	}
+	defer f.Close()
+
+	cutoff := time.Time{}
+	if s.opts.Retention > 0 {
+		cutoff = time.Now().Add(-s.opts.Retention)
+	}
+
+	skipped := 0
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16<<20)
+	for scanner.Scan() {
+		var rec fileRecord
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			skipped++
+			continue
+		}
+
+		switch rec.Op {
+		case "save":
+			if rec.Event == nil || rec.Event.Timestamp.Before(cutoff) {
+				continue
+			}
+			s.index.restore(*rec.Event, 0, rec.History)
+		case "transition":
+			if rec.Change != nil {
+				_ = s.index.Transition(rec.ID, *rec.Change)
+			}
+		case "status":
+			_ = s.index.UpdateStatus(rec.ID, rec.Status)
+		case "attempts":
+			_ = s.index.UpdateAttempts(rec.ID, rec.Attempts)
+		case "delete":
+			_ = s.index.Delete(rec.ID)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("reading segment %d: %w", seq, err)
+	}
+	if skipped > 0 {
+		s.opts.Logger.Warn("skipped undecodable records in event store segment",
+			"segment", s.segmentPath(seq), "skipped", skipped)
+	}
+	return nil
+}
+
+// openActive opens the active segment for appending. Callers must hold s.mu
+// or be constructing the store.
+//
+// A segment left ending in a partial line by a crash is first truncated back
+// to its last complete record, so the next record starts on a line of its own.
+func (s *FileStore) openActive() error {
+	f, err := os.OpenFile(s.segmentPath(s.seq), os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("opening segment: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("opening segment: %w", err)
+	}
+	size, err := trimTornTail(f, info.Size())
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("opening segment: %w", err)
+	}
+	s.active = f
+	s.size = size
+	return nil
+}
+
+// trimTornTail truncates f, of the given size, to just after its last newline
+// and returns the new size. A file already ending in a newline is left alone.
+func trimTornTail(f *os.File, size int64) (int64, error) {
+	buf := make([]byte, 4096)
+	for end := size; end > 0; {
+		start := max(end-int64(len(buf)), 0)
+		chunk := buf[:end-start]
+		if _, err := f.ReadAt(chunk, start); err != nil && err != io.EOF {
+			return 0, err
+		}
+		if i := bytes.LastIndexByte(chunk, '\n'); i >= 0 {
+			end = start + int64(i) + 1
+			if end == size {
+				return size, nil
+			}
+			return end, f.Truncate(end)
+		}
+		end = start
+	}
+	if size == 0 {
+		return 0, nil
+	}
+	return 0, f.Truncate(0)
+}
+
+// segments returns the numbers of the segment files in the store directory, ascending.
+func (s *FileStore) segments() ([]int, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, fmt.Errorf("reading store dir: %w", err)
+	}
+
+	var seqs []int
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasPrefix(name, segmentPrefix) || !strings.HasSuffix(name, segmentSuffix) {
+			continue
+		}
+		var seq int
+		if _, err := fmt.Sscanf(strings.TrimSuffix(strings.TrimPrefix(name, segmentPrefix), segmentSuffix), "%d", &seq); err != nil {
+			continue
+		}
+		seqs = append(seqs, seq)
+	}
+	sort.Ints(seqs)
+	return seqs, nil
+}
+
+func (s *FileStore) segmentPath(seq int) string {
+	return filepath.Join(s.dir, fmt.Sprintf("%s%06d%s", segmentPrefix, seq, segmentSuffix))
+}
@@ -0,0 +1,284 @@
+package event
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/youmna-rabie/claude-pod/internal/types"
+)
+
+func newTestFileStore(t *testing.T, dir string, opts FileOptions) *FileStore {
+	t.Helper()
+	store, err := NewFileStore(dir, opts)
+	if err != nil {
+		t.Fatalf("NewFileStore: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+func segmentCount(t *testing.T, dir string) int {
+	t.Helper()
+	matches, err := filepath.Glob(filepath.Join(dir, segmentPrefix+"*"+segmentSuffix))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return len(matches)
+}
+
+func TestNewFileStore_InvalidCapacity(t *testing.T) {
+	if _, err := NewFileStore(t.TempDir(), FileOptions{}); err != ErrInvalidCapacity {
+		t.Errorf("error = %v, want ErrInvalidCapacity", err)
+	}
+}
+
+func TestFileStoreSaveGetList(t *testing.T) {
+	store := newTestFileStore(t, t.TempDir(), FileOptions{Capacity: 10})
+
+	events := make([]types.Event, 3)
+	for i := range events {
+		events[i] = makeEvent("ch")
+		if err := store.Save(events[i]); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+	}
+
+	got, err := store.Get(events[1].ID)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.ID != events[1].ID {
+		t.Errorf("Get ID = %v, want %v", got.ID, events[1].ID)
+	}
+
+	listed, _ := store.List(10, 0)
+	if len(listed) != 3 || listed[0].ID != events[2].ID || listed[2].ID != events[0].ID {
+		t.Error("List should return events newest-first")
+	}
+	if c := store.Count(); c != 3 {
+		t.Errorf("Count = %d, want 3", c)
+	}
+
+	if _, err := store.Get(uuid.New()); err != ErrNotFound {
+		t.Errorf("Get unknown ID: error = %v, want ErrNotFound", err)
+	}
+	if err := store.UpdateStatus(uuid.New(), types.EventStatusFailed); err != ErrNotFound {
+		t.Errorf("UpdateStatus unknown ID: error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestFileStoreReplaysOnReopen(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewFileStore(dir, FileOptions{Capacity: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first, second := makeEvent("a"), makeEvent("b")
+	store.Save(first)
+	store.Save(second)
+	store.UpdateStatus(first.ID, types.EventStatusForwarded)
+	store.UpdateAttempts(first.ID, 2)
+	store.UpdateStatus(second.ID, types.EventStatusFailed)
+	store.Close()
+
+	reopened := newTestFileStore(t, dir, FileOptions{Capacity: 10})
+
+	got, err := reopened.Get(first.ID)
+	if err != nil {
+		t.Fatalf("Get after reopen: %v", err)
+	}
+	if got.Status != types.EventStatusForwarded || got.Attempts != 2 {
+		t.Errorf("replayed event = %+v, want forwarded with 2 attempts", got)
+	}
+	if got, _ := reopened.Get(second.ID); got.Status != types.EventStatusFailed {
+		t.Errorf("replayed status = %q, want %q", got.Status, types.EventStatusFailed)
+	}
+
+	listed, _ := reopened.List(10, 0)
+	if len(listed) != 2 || listed[0].ID != second.ID {
+		t.Error("replay should preserve insertion order")
+	}
+}
+
+func TestFileStoreDeletePersists(t *testing.T) {
+	dir := t.TempDir()
+	store, _ := NewFileStore(dir, FileOptions{Capacity: 10})
+	kept, deleted := makeEvent("ch"), makeEvent("ch")
+	store.Save(kept)
+	store.Save(deleted)
+	if err := store.Delete(deleted.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if err := store.Delete(deleted.ID); err != ErrNotFound {
+		t.Errorf("Delete twice: error = %v, want ErrNotFound", err)
+	}
+	store.Close()
+
+	reopened := newTestFileStore(t, dir, FileOptions{Capacity: 10})
+	if _, err := reopened.Get(deleted.ID); err != ErrNotFound {
+		t.Errorf("deleted event after reopen: error = %v, want ErrNotFound", err)
+	}
+	if c := reopened.Count(); c != 1 {
+		t.Errorf("Count = %d, want 1", c)
+	}
+}
+
+func TestFileStoreRotatesAndCompacts(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewFileStore(dir, FileOptions{Capacity: 100, SegmentSize: 512})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	events := make([]types.Event, 20)
+	for i := range events {
+		events[i] = makeEvent("ch")
+		if err := store.Save(events[i]); err != nil {
+			t.Fatalf("Save[%d]: %v", i, err)
+		}
+		store.UpdateStatus(events[i].ID, types.EventStatusForwarded)
+	}
+
+	// Compaction folds closed segments into one, leaving it plus the active segment.
+	if n := segmentCount(t, dir); n > 2 {
+		t.Errorf("segment files = %d, want at most 2 after compaction", n)
+	}
+	store.Close()
+
+	reopened := newTestFileStore(t, dir, FileOptions{Capacity: 100, SegmentSize: 512})
+	if c := reopened.Count(); c != len(events) {
+		t.Fatalf("Count after reopen = %d, want %d", c, len(events))
+	}
+	listed, _ := reopened.List(100, 0)
+	for i, ev := range listed {
+		want := events[len(events)-1-i]
+		if ev.ID != want.ID {
+			t.Fatalf("List[%d].ID = %v, want %v", i, ev.ID, want.ID)
+		}
+		if ev.Status != types.EventStatusForwarded {
+			t.Errorf("List[%d].Status = %q, want forwarded", i, ev.Status)
+		}
+	}
+}
+
+func TestFileStoreCompactionAppliesRetention(t *testing.T) {
+	dir := t.TempDir()
+	opts := FileOptions{Capacity: 100, SegmentSize: 256, Retention: time.Hour}
+	store := newTestFileStore(t, dir, opts)
+
+	old := makeEvent("ch")
+	old.Timestamp = time.Now().Add(-2 * time.Hour)
+	store.Save(old)
+
+	// Write enough fresh events to force at least one rotation.
+	for i := 0; i < 5; i++ {
+		store.Save(makeEvent("ch"))
+	}
+
+	if _, err := store.Get(old.ID); err != ErrNotFound {
+		t.Errorf("expired event after compaction: error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestFileStoreReplayAppliesRetention(t *testing.T) {
+	dir := t.TempDir()
+	store, _ := NewFileStore(dir, FileOptions{Capacity: 10})
+	old := makeEvent("ch")
+	old.Timestamp = time.Now().Add(-2 * time.Hour)
+	fresh := makeEvent("ch")
+	store.Save(old)
+	store.Save(fresh)
+	store.Close()
+
+	reopened := newTestFileStore(t, dir, FileOptions{Capacity: 10, Retention: time.Hour})
+	if _, err := reopened.Get(old.ID); err != ErrNotFound {
+		t.Errorf("expired event after replay: error = %v, want ErrNotFound", err)
+	}
+	if _, err := reopened.Get(fresh.ID); err != nil {
+		t.Errorf("fresh event after replay: %v", err)
+	}
+}
+
+func TestFileStoreSkipsTornRecords(t *testing.T) {
+	dir := t.TempDir()
+	store, _ := NewFileStore(dir, FileOptions{Capacity: 10})
+	ev := makeEvent("ch")
+	store.Save(ev)
+	store.Close()
+
+	// Simulate a crash mid-write.
+	f, err := os.OpenFile(filepath.Join(dir, "segment-000001.jsonl"), os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"op":"save","event":{"id":`)
+	f.Close()
+
+	reopened := newTestFileStore(t, dir, FileOptions{Capacity: 10})
+	if _, err := reopened.Get(ev.ID); err != nil {
+		t.Errorf("Get after torn write: %v", err)
+	}
+	if c := reopened.Count(); c != 1 {
+		t.Errorf("Count = %d, want 1", c)
+	}
+}
+
+func TestFileStoreAppendsAfterTornRecord(t *testing.T) {
+	dir := t.TempDir()
+	store, _ := NewFileStore(dir, FileOptions{Capacity: 10})
+	first := makeEvent("ch")
+	store.Save(first)
+	store.Close()
+
+	f, err := os.OpenFile(filepath.Join(dir, "segment-000001.jsonl"), os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"op":"save","event":{"id":`)
+	f.Close()
+
+	// The first record written after the crash must not be joined onto the
+	// torn line.
+	reopened, err := NewFileStore(dir, FileOptions{Capacity: 10})
+	if err != nil {
+		t.Fatalf("NewFileStore: %v", err)
+	}
+	second := makeEvent("ch")
+	if err := reopened.Save(second); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	reopened.Close()
+
+	again := newTestFileStore(t, dir, FileOptions{Capacity: 10})
+	for _, ev := range []types.Event{first, second} {
+		if _, err := again.Get(ev.ID); err != nil {
+			t.Errorf("Get(%s) after restart: %v", ev.ID, err)
+		}
+	}
+}
+
+func TestFileStoreCapacity(t *testing.T) {
+	dir := t.TempDir()
+	store, _ := NewFileStore(dir, FileOptions{Capacity: 2})
+	events := []types.Event{makeEvent("ch"), makeEvent("ch"), makeEvent("ch")}
+	for _, ev := range events {
+		store.Save(ev)
+	}
+	store.Close()
+
+	reopened := newTestFileStore(t, dir, FileOptions{Capacity: 2})
+	if c := reopened.Count(); c != 2 {
+		t.Errorf("Count = %d, want 2", c)
+	}
+	if _, err := reopened.Get(events[0].ID); err != ErrNotFound {
+		t.Errorf("oldest event: error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestFileStoreInterface(t *testing.T) {
+	var _ Store = (*FileStore)(nil)
+}
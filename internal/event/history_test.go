@@ -0,0 +1,175 @@
+package event
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/youmna-rabie/claude-pod/internal/types"
+)
+
+// testStoreHistory exercises the Transition and History contract against any Store implementation.
+func testStoreHistory(t *testing.T, store Store) types.Event {
+	t.Helper()
+
+	ev := makeEvent("grafana")
+	if err := store.Save(ev); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if err := store.Transition(ev.ID, types.StatusChange{
+		Status:  types.EventStatusFailed,
+		Error:   "agent returned status 503",
+		Attempt: 3,
+	}); err != nil {
+		t.Fatalf("Transition: %v", err)
+	}
+	if err := store.Transition(ev.ID, types.StatusChange{
+		Status:   types.EventStatusForwarded,
+		Attempt:  1,
+		Response: json.RawMessage(`{"reply":"done"}`),
+		Replay:   true,
+	}); err != nil {
+		t.Fatalf("Transition: %v", err)
+	}
+	if err := store.UpdateStatus(ev.ID, types.EventStatusCompleted); err != nil {
+		t.Fatalf("UpdateStatus: %v", err)
+	}
+
+	checkHistory(t, store, ev)
+
+	if err := store.Transition(uuid.New(), types.StatusChange{Status: types.EventStatusFailed}); err != ErrNotFound {
+		t.Errorf("Transition unknown ID: error = %v, want ErrNotFound", err)
+	}
+	if _, err := store.History(uuid.New()); err != ErrNotFound {
+		t.Errorf("History unknown ID: error = %v, want ErrNotFound", err)
+	}
+	return ev
+}
+
+// checkHistory verifies the timeline written by testStoreHistory.
+func checkHistory(t *testing.T, store Store, ev types.Event) {
+	t.Helper()
+
+	got, err := store.Get(ev.ID)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.Status != types.EventStatusCompleted || got.Attempts != 1 {
+		t.Errorf("event = %s with %d attempts, want completed with 1", got.Status, got.Attempts)
+	}
+
+	history, err := store.History(ev.ID)
+	if err != nil {
+		t.Fatalf("History: %v", err)
+	}
+	want := []types.EventStatus{
+		types.EventStatusReceived,
+		types.EventStatusFailed,
+		types.EventStatusForwarded,
+		types.EventStatusCompleted,
+	}
+	if len(history) != len(want) {
+		t.Fatalf("history has %d entries, want %d: %+v", len(history), len(want), history)
+	}
+	for i, status := range want {
+		if history[i].Status != status {
+			t.Errorf("history[%d].Status = %q, want %q", i, history[i].Status, status)
+		}
+		if history[i].Timestamp.IsZero() {
+			t.Errorf("history[%d].Timestamp is zero", i)
+		}
+		if i > 0 && history[i].Timestamp.Before(history[i-1].Timestamp) {
+			t.Errorf("history[%d] is older than the entry before it", i)
+		}
+	}
+	if !history[0].Timestamp.Equal(ev.Timestamp) {
+		t.Errorf("initial entry timestamp = %v, want event timestamp %v", history[0].Timestamp, ev.Timestamp)
+	}
+	if history[1].Error != "agent returned status 503" || history[1].Attempt != 3 {
+		t.Errorf("failure entry = %+v, want error and attempt 3", history[1])
+	}
+	if string(history[2].Response) != `{"reply":"done"}` {
+		t.Errorf("forwarded entry response = %s, want agent response", history[2].Response)
+	}
+	for i, change := range history {
+		if change.Replay != (i == 2) {
+			t.Errorf("history[%d].Replay = %t, want it set only on the replayed forward", i, change.Replay)
+		}
+	}
+}
+
+func TestMemoryStoreHistory(t *testing.T) {
+	store, _ := NewMemoryStore(10)
+	testStoreHistory(t, store)
+}
+
+func TestMemoryStoreHistoryIsCopied(t *testing.T) {
+	store, _ := NewMemoryStore(10)
+	ev := makeEvent("ch")
+	store.Save(ev)
+
+	history, _ := store.History(ev.ID)
+	history[0].Status = types.EventStatusFailed
+
+	if again, _ := store.History(ev.ID); again[0].Status != types.EventStatusReceived {
+		t.Error("modifying a returned history changed the stored history")
+	}
+}
+
+func TestSQLiteStoreHistory(t *testing.T) {
+	store, path := newTestSQLiteStore(t, 10)
+	ev := testStoreHistory(t, store)
+	store.Close()
+
+	reopened, err := NewSQLiteStore(path, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reopened.Close()
+	checkHistory(t, reopened, ev)
+}
+
+func TestSQLiteStoreHistoryEvicted(t *testing.T) {
+	store, _ := newTestSQLiteStore(t, 1)
+	first := makeEvent("ch")
+	store.Save(first)
+	store.UpdateStatus(first.ID, types.EventStatusFailed)
+	store.Save(makeEvent("ch"))
+
+	var rows int
+	if err := store.db.QueryRow(`SELECT COUNT(*) FROM event_history WHERE event_id = ?`, first.ID.String()).Scan(&rows); err != nil {
+		t.Fatal(err)
+	}
+	if rows != 0 {
+		t.Errorf("evicted event left %d history rows, want 0", rows)
+	}
+}
+
+func TestFileStoreHistory(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewFileStore(dir, FileOptions{Capacity: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ev := testStoreHistory(t, store)
+	store.Close()
+
+	checkHistory(t, newTestFileStore(t, dir, FileOptions{Capacity: 10}), ev)
+}
+
+func TestFileStoreHistorySurvivesCompaction(t *testing.T) {
+	dir := t.TempDir()
+	opts := FileOptions{Capacity: 100, SegmentSize: 1 << 10}
+	store, err := NewFileStore(dir, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ev := testStoreHistory(t, store)
+	for i := 0; i < 20; i++ {
+		store.Save(makeEvent("other"))
+	}
+	checkHistory(t, store, ev)
+	store.Close()
+
+	checkHistory(t, newTestFileStore(t, dir, opts), ev)
+}
@@ -2,7 +2,9 @@ package event
 
 import (
 	"errors"
+	"slices"
 	"sync"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/youmna-rabie/claude-pod/internal/types"
@@ -17,11 +19,16 @@ var (
 // It provides O(1) lookups by ID via a map index and is safe for concurrent use.
 type MemoryStore struct {
 	mu    sync.RWMutex
-	buf   []types.Event // ring buffer
-	index map[uuid.UUID]int // event ID → position in buf
-	cap   int               // maximum capacity
-	count int               // current number of stored events
-	head  int               // next write position
+	buf   []types.Event          // ring buffer
+	seqs  []uint64               // insertion sequence of each slot in buf, for query cursors
+	hist  [][]types.StatusChange // status history of each slot in buf
+	index map[uuid.UUID]int      // event ID → position in buf
+	cap   int                    // maximum capacity
+	count int                    // current number of stored events
+	head  int                    // next write position
+	next  uint64                 // sequence assigned to the next saved event
+
+	evictions uint64 // events overwritten because the buffer was full
 }
 
 // NewMemoryStore creates a MemoryStore with the given capacity.
@@ -31,8 +38,11 @@ func NewMemoryStore(capacity int) (*MemoryStore, error) {
 	}
 	return &MemoryStore{
 		buf:   make([]types.Event, capacity),
+		seqs:  make([]uint64, capacity),
+		hist:  make([][]types.StatusChange, capacity),
 		index: make(map[uuid.UUID]int, capacity),
 		cap:   capacity,
+		next:  1,
 	}, nil
 }
 
@@ -41,21 +51,41 @@ func (s *MemoryStore) Save(event types.Event) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	s.save(event, s.next, nil)
+	return nil
+}
+
+// save writes event into the next slot with the given sequence and history.
+// A nil history starts one from the event's current status. Callers must hold s.mu.
+func (s *MemoryStore) save(event types.Event, seq uint64, history []types.StatusChange) {
+	if history == nil {
+		history = []types.StatusChange{{Status: event.Status, Timestamp: event.Timestamp}}
+	}
+
 	// If overwriting an existing slot, remove the old event from the index.
 	if s.count == s.cap {
 		old := s.buf[s.head]
 		delete(s.index, old.ID)
+		s.evictions++
 	}
 
 	s.buf[s.head] = event
+	s.seqs[s.head] = seq
+	s.hist[s.head] = history
 	s.index[event.ID] = s.head
+	s.next = max(s.next, seq+1)
 
 	s.head = (s.head + 1) % s.cap
 	if s.count < s.cap {
 		s.count++
 	}
+}
 
-	return nil
+// Evictions returns how many events have been dropped to make room for newer ones.
+func (s *MemoryStore) Evictions() uint64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.evictions
 }
 
 // Get retrieves an event by ID in O(1) time.
@@ -99,8 +129,44 @@ func (s *MemoryStore) List(limit, offset int) ([]types.Event, error) {
 	return result, nil
 }
 
-// UpdateStatus changes the status of an event identified by ID.
+// Query returns one page of events matching q, newest-first.
+func (s *MemoryStore) Query(q Query) (Page, error) {
+	before, err := decodeCursor(q.Cursor)
+	if err != nil {
+		return Page{}, err
+	}
+	limit := q.limit()
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	page := Page{Events: []types.Event{}}
+	var last uint64
+	for i := 0; i < s.count; i++ {
+		pos := (s.head - 1 - i + s.cap) % s.cap
+		if before != 0 && s.seqs[pos] >= before {
+			continue
+		}
+		if !q.Match(s.buf[pos]) {
+			continue
+		}
+		if len(page.Events) == limit {
+			page.NextCursor = encodeCursor(last)
+			break
+		}
+		page.Events = append(page.Events, s.buf[pos])
+		last = s.seqs[pos]
+	}
+	return page, nil
+}
+
+// UpdateStatus changes the status of an event identified by ID and records it in the history.
 func (s *MemoryStore) UpdateStatus(id uuid.UUID, status types.EventStatus) error {
+	return s.Transition(id, types.StatusChange{Status: status})
+}
+
+// Transition changes an event's status and appends change to its history.
+func (s *MemoryStore) Transition(id uuid.UUID, change types.StatusChange) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -108,10 +174,100 @@ func (s *MemoryStore) UpdateStatus(id uuid.UUID, status types.EventStatus) error
 	if !ok {
 		return ErrNotFound
 	}
-	s.buf[pos].Status = status
+	if change.Timestamp.IsZero() {
+		change.Timestamp = time.Now()
+	}
+	s.buf[pos].Status = change.Status
+	if change.Attempt > 0 {
+		s.buf[pos].Attempts = change.Attempt
+	}
+	s.hist[pos] = append(s.hist[pos], change)
 	return nil
 }
 
+// History returns an event's status changes, oldest first.
+func (s *MemoryStore) History(id uuid.UUID) ([]types.StatusChange, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	pos, ok := s.index[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return slices.Clone(s.hist[pos]), nil
+}
+
+// UpdateAttempts records the number of forward attempts for an event identified by ID.
+func (s *MemoryStore) UpdateAttempts(id uuid.UUID, attempts int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pos, ok := s.index[id]
+	if !ok {
+		return ErrNotFound
+	}
+	s.buf[pos].Attempts = attempts
+	return nil
+}
+
+// Delete removes an event, shifting newer events back to close the gap.
+func (s *MemoryStore) Delete(id uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pos, ok := s.index[id]
+	if !ok {
+		return ErrNotFound
+	}
+	delete(s.index, id)
+
+	for {
+		next := (pos + 1) % s.cap
+		if next == s.head {
+			break
+		}
+		s.buf[pos], s.seqs[pos], s.hist[pos] = s.buf[next], s.seqs[next], s.hist[next]
+		s.index[s.buf[pos].ID] = pos
+		pos = next
+	}
+	s.buf[pos], s.seqs[pos], s.hist[pos] = types.Event{}, 0, nil
+	s.head = pos
+	s.count--
+	return nil
+}
+
+// each calls fn for every stored event with its sequence and history, oldest-first.
+func (s *MemoryStore) each(fn func(seq uint64, event types.Event, history []types.StatusChange)) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for i := s.count - 1; i >= 0; i-- {
+		pos := (s.head - 1 - i + s.cap) % s.cap
+		fn(s.seqs[pos], s.buf[pos], s.hist[pos])
+	}
+}
+
+// restore saves an event under an existing sequence and history, so that
+// query cursors and timelines survive when another store rebuilds its index.
+// An event already present is overwritten in place. A zero seq assigns the
+// next sequence.
+func (s *MemoryStore) restore(event types.Event, seq uint64, history []types.StatusChange) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if pos, ok := s.index[event.ID]; ok {
+		s.buf[pos] = event
+		if history != nil {
+			s.hist[pos] = history
+		}
+		return
+	}
+	if seq == 0 {
+		seq = s.next
+	}
+	s.save(event, seq, history)
+}
+
 // Count returns the number of events currently stored.
 func (s *MemoryStore) Count() int {
 	s.mu.RLock()
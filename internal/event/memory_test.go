@@ -100,6 +100,24 @@ func TestEvictionAtCapacity(t *testing.T) {
 	}
 }
 
+func TestEvictions(t *testing.T) {
+	store, _ := NewMemoryStore(3)
+	for i := 0; i < 5; i++ {
+		store.Save(makeEvent("ch"))
+	}
+	if n := store.Evictions(); n != 2 {
+		t.Errorf("Evictions = %d, want 2", n)
+	}
+
+	// Deleting frees a slot without counting as an eviction.
+	listed, _ := store.List(1, 0)
+	store.Delete(listed[0].ID)
+	store.Save(makeEvent("ch"))
+	if n := store.Evictions(); n != 2 {
+		t.Errorf("Evictions after delete and save = %d, want 2", n)
+	}
+}
+
 func TestListNewestFirst(t *testing.T) {
 	store, _ := NewMemoryStore(10)
 
@@ -185,6 +203,51 @@ func TestListWithEviction(t *testing.T) {
 	}
 }
 
+func TestDelete(t *testing.T) {
+	// Capacity 4 with 6 saves wraps the ring so the gap closes across the boundary.
+	store, _ := NewMemoryStore(4)
+	events := make([]types.Event, 6)
+	for i := range events {
+		events[i] = makeEvent("ch")
+		store.Save(events[i])
+	}
+
+	if err := store.Delete(events[3].ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := store.Get(events[3].ID); err != ErrNotFound {
+		t.Errorf("Get deleted event: error = %v, want ErrNotFound", err)
+	}
+	if c := store.Count(); c != 3 {
+		t.Errorf("Count = %d, want 3", c)
+	}
+
+	listed, _ := store.List(10, 0)
+	want := []types.Event{events[5], events[4], events[2]}
+	for i, ev := range want {
+		if listed[i].ID != ev.ID {
+			t.Errorf("List[%d] = %v, want %v", i, listed[i].ID, ev.ID)
+		}
+		if got, err := store.Get(ev.ID); err != nil || got.ID != ev.ID {
+			t.Errorf("Get(%v) after delete = %v, %v", ev.ID, got.ID, err)
+		}
+	}
+
+	// The freed slot is reused before anything else is evicted.
+	next := makeEvent("ch")
+	store.Save(next)
+	if _, err := store.Get(events[2].ID); err != nil {
+		t.Errorf("oldest event evicted despite free slot: %v", err)
+	}
+	if listed, _ := store.List(1, 0); listed[0].ID != next.ID {
+		t.Error("newest event after delete and save has wrong ID")
+	}
+
+	if err := store.Delete(uuid.New()); err != ErrNotFound {
+		t.Errorf("Delete unknown ID: error = %v, want ErrNotFound", err)
+	}
+}
+
 func TestUpdateStatus(t *testing.T) {
 	store, _ := NewMemoryStore(10)
 
@@ -210,6 +273,26 @@ func TestUpdateStatusNotFound(t *testing.T) {
 	}
 }
 
+func TestUpdateAttempts(t *testing.T) {
+	store, _ := NewMemoryStore(10)
+
+	ev := makeEvent("slack")
+	store.Save(ev)
+
+	if err := store.UpdateAttempts(ev.ID, 3); err != nil {
+		t.Fatalf("UpdateAttempts: %v", err)
+	}
+
+	got, _ := store.Get(ev.ID)
+	if got.Attempts != 3 {
+		t.Errorf("Attempts = %d, want 3", got.Attempts)
+	}
+
+	if err := store.UpdateAttempts(uuid.New(), 1); err != ErrNotFound {
+		t.Errorf("UpdateAttempts unknown ID: error = %v, want ErrNotFound", err)
+	}
+}
+
 func TestCount(t *testing.T) {
 	store, _ := NewMemoryStore(5)
 
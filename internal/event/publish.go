@@ -0,0 +1,144 @@
+package event
+
+import (
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/youmna-rabie/claude-pod/internal/types"
+)
+
+// NotificationType distinguishes newly saved events from status changes.
+type NotificationType string
+
+const (
+	NotifySaved      NotificationType = "event"
+	NotifyTransition NotificationType = "status"
+)
+
+// Notification describes one change published by a PublishingStore.
+type Notification struct {
+	Type   NotificationType    `json:"type"`
+	Event  types.Event         `json:"event"`
+	Change *types.StatusChange `json:"change,omitempty"`
+}
+
+// Subscription receives notifications from a PublishingStore until closed.
+// A subscriber that falls a full buffer behind is dropped and C is closed,
+// so a slow reader never blocks writers.
+type Subscription struct {
+	C <-chan Notification
+
+	ch    chan Notification
+	store *PublishingStore
+}
+
+// Close stops the subscription. It is safe to call more than once.
+func (sub *Subscription) Close() {
+	sub.store.unsubscribe(sub)
+}
+
+// PublishingStore wraps a Store and publishes every save and status change
+// to its subscribers. Reads and other writes pass straight through.
+type PublishingStore struct {
+	Store
+
+	mu   sync.Mutex
+	subs map[*Subscription]struct{}
+}
+
+// NewPublishingStore wraps store.
+func NewPublishingStore(store Store) *PublishingStore {
+	return &PublishingStore{
+		Store: store,
+		subs:  make(map[*Subscription]struct{}),
+	}
+}
+
+// Subscribe registers a subscriber whose channel holds up to buffer
+// notifications.
+func (s *PublishingStore) Subscribe(buffer int) *Subscription {
+	ch := make(chan Notification, buffer)
+	sub := &Subscription{C: ch, ch: ch, store: s}
+
+	s.mu.Lock()
+	s.subs[sub] = struct{}{}
+	s.mu.Unlock()
+	return sub
+}
+
+func (s *PublishingStore) unsubscribe(sub *Subscription) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.subs[sub]; ok {
+		delete(s.subs, sub)
+		close(sub.ch)
+	}
+}
+
+// subscribed reports whether anyone is listening, so writes can skip the
+// extra reads needed to build a notification.
+func (s *PublishingStore) subscribed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.subs) > 0
+}
+
+func (s *PublishingStore) publish(n Notification) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for sub := range s.subs {
+		select {
+		case sub.ch <- n:
+		default:
+			delete(s.subs, sub)
+			close(sub.ch)
+		}
+	}
+}
+
+// Save saves event and publishes it.
+func (s *PublishingStore) Save(event types.Event) error {
+	if err := s.Store.Save(event); err != nil {
+		return err
+	}
+	if s.subscribed() {
+		s.publish(Notification{Type: NotifySaved, Event: event})
+	}
+	return nil
+}
+
+// Transition records change and publishes the updated event.
+func (s *PublishingStore) Transition(id uuid.UUID, change types.StatusChange) error {
+	if err := s.Store.Transition(id, change); err != nil {
+		return err
+	}
+	s.publishTransition(id)
+	return nil
+}
+
+// UpdateStatus changes the event's status and publishes the updated event.
+func (s *PublishingStore) UpdateStatus(id uuid.UUID, status types.EventStatus) error {
+	if err := s.Store.UpdateStatus(id, status); err != nil {
+		return err
+	}
+	s.publishTransition(id)
+	return nil
+}
+
+// publishTransition publishes the event's current state with the change the
+// store recorded last, which carries the timestamp the store assigned.
+func (s *PublishingStore) publishTransition(id uuid.UUID) {
+	if !s.subscribed() {
+		return
+	}
+	event, err := s.Store.Get(id)
+	if err != nil {
+		return
+	}
+	history, err := s.Store.History(id)
+	if err != nil || len(history) == 0 {
+		return
+	}
+	change := history[len(history)-1]
+	s.publish(Notification{Type: NotifyTransition, Event: event, Change: &change})
+}
@@ -0,0 +1,107 @@
+package event
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/youmna-rabie/claude-pod/internal/types"
+)
+
+func receive(t *testing.T, sub *Subscription) Notification {
+	t.Helper()
+	select {
+	case n, ok := <-sub.C:
+		if !ok {
+			t.Fatal("subscription closed")
+		}
+		return n
+	case <-time.After(time.Second):
+		t.Fatal("no notification received")
+	}
+	return Notification{}
+}
+
+func TestPublishingStoreNotifiesSubscribers(t *testing.T) {
+	mem, _ := NewMemoryStore(10)
+	store := NewPublishingStore(mem)
+	sub := store.Subscribe(10)
+	defer sub.Close()
+
+	ev := makeEvent("grafana")
+	if err := store.Save(ev); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	n := receive(t, sub)
+	if n.Type != NotifySaved || n.Event.ID != ev.ID || n.Change != nil {
+		t.Errorf("save notification = %+v", n)
+	}
+
+	if err := store.Transition(ev.ID, types.StatusChange{Status: types.EventStatusFailed, Error: "boom", Attempt: 2}); err != nil {
+		t.Fatalf("Transition: %v", err)
+	}
+	n = receive(t, sub)
+	if n.Type != NotifyTransition || n.Event.Status != types.EventStatusFailed || n.Event.Attempts != 2 {
+		t.Errorf("transition notification event = %+v", n.Event)
+	}
+	if n.Change == nil || n.Change.Error != "boom" || n.Change.Timestamp.IsZero() {
+		t.Errorf("transition notification change = %+v, want error and stamped time", n.Change)
+	}
+
+	store.UpdateStatus(ev.ID, types.EventStatusCompleted)
+	if n := receive(t, sub); n.Change == nil || n.Change.Status != types.EventStatusCompleted {
+		t.Errorf("UpdateStatus notification = %+v", n)
+	}
+
+	// Failed writes and attempt updates publish nothing.
+	store.Transition(uuid.New(), types.StatusChange{Status: types.EventStatusFailed})
+	store.UpdateAttempts(ev.ID, 3)
+	select {
+	case n := <-sub.C:
+		t.Errorf("unexpected notification %+v", n)
+	default:
+	}
+}
+
+func TestPublishingStoreDropsSlowSubscriber(t *testing.T) {
+	mem, _ := NewMemoryStore(10)
+	store := NewPublishingStore(mem)
+	slow := store.Subscribe(1)
+	fast := store.Subscribe(10)
+	defer fast.Close()
+
+	store.Save(makeEvent("ch"))
+	store.Save(makeEvent("ch"))
+
+	receive(t, slow)
+	if _, ok := <-slow.C; ok {
+		t.Error("slow subscriber still open after its buffer overflowed")
+	}
+	receive(t, fast)
+	receive(t, fast)
+
+	// Closing a dropped subscription is harmless.
+	slow.Close()
+	slow.Close()
+}
+
+func TestPublishingStoreUnsubscribe(t *testing.T) {
+	mem, _ := NewMemoryStore(10)
+	store := NewPublishingStore(mem)
+	sub := store.Subscribe(10)
+	sub.Close()
+
+	if err := store.Save(makeEvent("ch")); err != nil {
+		t.Fatalf("Save after unsubscribe: %v", err)
+	}
+	if _, ok := <-sub.C; ok {
+		t.Error("closed subscription received a notification")
+	}
+	if store.Count() != 1 {
+		t.Errorf("Count = %d, want 1", store.Count())
+	}
+}
+
+func TestPublishingStoreInterface(t *testing.T) {
+	var _ Store = (*PublishingStore)(nil)
+}
@@ -0,0 +1,113 @@
+package event
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/youmna-rabie/claude-pod/internal/types"
+)
+
+const (
+	// DefaultQueryLimit is the page size used when Query.Limit is unset.
+	DefaultQueryLimit = 50
+	// MaxQueryLimit caps the page size of a single query.
+	MaxQueryLimit = 1000
+)
+
+// ErrInvalidCursor is returned when a query cursor cannot be decoded.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Query filters and pages through stored events. Zero-valued fields match
+// everything. Results are ordered newest-first.
+type Query struct {
+	ChannelID string
+	Status    types.EventStatus
+	Since     time.Time // inclusive lower bound on Timestamp
+	Until     time.Time // exclusive upper bound on Timestamp
+
+	// HeaderContains matches events with a header whose "Name: value" form
+	// contains the substring, case-insensitively.
+	HeaderContains string
+	// BodyContains matches events whose raw body contains the substring.
+	BodyContains string
+
+	// Cursor resumes a previous query; pass the NextCursor of the last page.
+	Cursor string
+	// Limit is the maximum number of events returned. Zero means
+	// DefaultQueryLimit; values above MaxQueryLimit are capped.
+	Limit int
+}
+
+// Page is one page of query results.
+type Page struct {
+	Events []types.Event `json:"events"`
+	// NextCursor fetches the following page; empty when there are no more results.
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// Match reports whether the event satisfies the query's filters.
+// Cursor and Limit are not considered.
+func (q Query) Match(e types.Event) bool {
+	if q.ChannelID != "" && e.ChannelID != q.ChannelID {
+		return false
+	}
+	if q.Status != "" && e.Status != q.Status {
+		return false
+	}
+	if !q.Since.IsZero() && e.Timestamp.Before(q.Since) {
+		return false
+	}
+	if !q.Until.IsZero() && !e.Timestamp.Before(q.Until) {
+		return false
+	}
+	if q.BodyContains != "" && !bytes.Contains(e.RawBody, []byte(q.BodyContains)) {
+		return false
+	}
+	if q.HeaderContains != "" && !headersContain(e.Headers, q.HeaderContains) {
+		return false
+	}
+	return true
+}
+
+func (q Query) limit() int {
+	switch {
+	case q.Limit <= 0:
+		return DefaultQueryLimit
+	case q.Limit > MaxQueryLimit:
+		return MaxQueryLimit
+	default:
+		return q.Limit
+	}
+}
+
+func headersContain(headers map[string]string, substr string) bool {
+	substr = strings.ToLower(substr)
+	for name, value := range headers {
+		if strings.Contains(strings.ToLower(name+": "+value), substr) {
+			return true
+		}
+	}
+	return false
+}
+
+// Cursors encode the store sequence number of the last event on a page; the
+// next page continues with strictly older events.
+
+func encodeCursor(seq uint64) string {
+	return strconv.FormatUint(seq, 36)
+}
+
+// decodeCursor returns the sequence to continue before, or 0 for no cursor.
+func decodeCursor(cursor string) (uint64, error) {
+	if cursor == "" {
+		return 0, nil
+	}
+	seq, err := strconv.ParseUint(cursor, 36, 64)
+	if err != nil || seq == 0 {
+		return 0, ErrInvalidCursor
+	}
+	return seq, nil
+}
@@ -0,0 +1,210 @@
+package event
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/youmna-rabie/claude-pod/internal/types"
+)
+
+func TestQueryMatch(t *testing.T) {
+	now := time.Now()
+	ev := types.Event{
+		ID:        uuid.New(),
+		ChannelID: "grafana",
+		RawBody:   json.RawMessage(`{"alert":"disk full"}`),
+		Headers:   map[string]string{"X-Grafana-Org": "42"},
+		Timestamp: now,
+		Status:    types.EventStatusFailed,
+	}
+
+	tests := []struct {
+		name  string
+		query Query
+		want  bool
+	}{
+		{"empty", Query{}, true},
+		{"channel", Query{ChannelID: "grafana"}, true},
+		{"other channel", Query{ChannelID: "slack"}, false},
+		{"status", Query{Status: types.EventStatusFailed}, true},
+		{"other status", Query{Status: types.EventStatusForwarded}, false},
+		{"since inclusive", Query{Since: now}, true},
+		{"since after", Query{Since: now.Add(time.Second)}, false},
+		{"until exclusive", Query{Until: now}, false},
+		{"until after", Query{Until: now.Add(time.Second)}, true},
+		{"body", Query{BodyContains: "disk full"}, true},
+		{"body missing", Query{BodyContains: "cpu"}, false},
+		{"header name", Query{HeaderContains: "x-grafana"}, true},
+		{"header value", Query{HeaderContains: "org: 42"}, true},
+		{"header missing", Query{HeaderContains: "X-Slack"}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.query.Match(ev); got != tt.want {
+			t.Errorf("%s: Match = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestQueryLimit(t *testing.T) {
+	for limit, want := range map[int]int{0: DefaultQueryLimit, -1: DefaultQueryLimit, 7: 7, MaxQueryLimit + 1: MaxQueryLimit} {
+		if got := (Query{Limit: limit}).limit(); got != want {
+			t.Errorf("limit(%d) = %d, want %d", limit, got, want)
+		}
+	}
+}
+
+// testStoreQuery exercises the Query contract against any Store implementation.
+func testStoreQuery(t *testing.T, store Store) {
+	t.Helper()
+	base := time.Now().Add(-time.Hour)
+
+	var saved []types.Event
+	for i := 0; i < 6; i++ {
+		ev := makeEvent("grafana")
+		if i%2 == 1 {
+			ev.ChannelID = "slack"
+			ev.Headers = map[string]string{"X-Slack-Signature": "v0=abc"}
+		}
+		ev.RawBody = json.RawMessage(`{"n":` + string(rune('0'+i)) + `}`)
+		ev.Timestamp = base.Add(time.Duration(i) * time.Minute)
+		if err := store.Save(ev); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+		saved = append(saved, ev)
+	}
+	store.UpdateStatus(saved[2].ID, types.EventStatusFailed)
+	store.UpdateStatus(saved[4].ID, types.EventStatusFailed)
+
+	ids := func(page Page) []uuid.UUID {
+		out := make([]uuid.UUID, len(page.Events))
+		for i, ev := range page.Events {
+			out[i] = ev.ID
+		}
+		return out
+	}
+	expect := func(name string, page Page, want ...types.Event) {
+		t.Helper()
+		got := ids(page)
+		if len(got) != len(want) {
+			t.Errorf("%s: got %d events, want %d", name, len(got), len(want))
+			return
+		}
+		for i := range want {
+			if got[i] != want[i].ID {
+				t.Errorf("%s: event[%d] = %v, want %v", name, i, got[i], want[i].ID)
+			}
+		}
+	}
+
+	page, err := store.Query(Query{ChannelID: "grafana", Status: types.EventStatusFailed})
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	expect("failed grafana", page, saved[4], saved[2])
+	if page.NextCursor != "" {
+		t.Errorf("NextCursor = %q, want empty on last page", page.NextCursor)
+	}
+
+	page, _ = store.Query(Query{Since: base.Add(2 * time.Minute), Until: base.Add(4 * time.Minute)})
+	expect("time range", page, saved[3], saved[2])
+
+	page, _ = store.Query(Query{HeaderContains: "slack-signature"})
+	expect("header", page, saved[5], saved[3], saved[1])
+
+	page, _ = store.Query(Query{BodyContains: `"n":0`})
+	expect("body", page, saved[0])
+
+	page, _ = store.Query(Query{ChannelID: "none"})
+	if page.Events == nil || len(page.Events) != 0 {
+		t.Errorf("no matches: Events = %v, want empty non-nil slice", page.Events)
+	}
+
+	// Walk every page of two.
+	var walked []uuid.UUID
+	q := Query{Limit: 2}
+	for {
+		page, err := store.Query(q)
+		if err != nil {
+			t.Fatalf("Query page: %v", err)
+		}
+		walked = append(walked, ids(page)...)
+		if page.NextCursor == "" {
+			break
+		}
+		q.Cursor = page.NextCursor
+	}
+	if len(walked) != len(saved) {
+		t.Fatalf("paged through %d events, want %d", len(walked), len(saved))
+	}
+	for i, id := range walked {
+		if want := saved[len(saved)-1-i].ID; id != want {
+			t.Errorf("paged[%d] = %v, want %v", i, id, want)
+		}
+	}
+
+	// Cursors combine with filters.
+	page, _ = store.Query(Query{HeaderContains: "slack", Limit: 1})
+	expect("filtered page 1", page, saved[5])
+	page, _ = store.Query(Query{HeaderContains: "slack", Limit: 1, Cursor: page.NextCursor})
+	expect("filtered page 2", page, saved[3])
+
+	if _, err := store.Query(Query{Cursor: "not a cursor!"}); err != ErrInvalidCursor {
+		t.Errorf("bad cursor: error = %v, want ErrInvalidCursor", err)
+	}
+}
+
+func TestMemoryStoreQuery(t *testing.T) {
+	store, _ := NewMemoryStore(10)
+	testStoreQuery(t, store)
+}
+
+func TestMemoryStoreQuerySkipsEvicted(t *testing.T) {
+	store, _ := NewMemoryStore(2)
+	first, second, third := makeEvent("ch"), makeEvent("ch"), makeEvent("ch")
+	store.Save(first)
+	store.Save(second)
+
+	page, _ := store.Query(Query{Limit: 1})
+	store.Save(third) // evicts first
+
+	page, _ = store.Query(Query{Limit: 1, Cursor: page.NextCursor})
+	if len(page.Events) != 0 {
+		t.Errorf("page after eviction = %d events, want 0", len(page.Events))
+	}
+}
+
+func TestSQLiteStoreQuery(t *testing.T) {
+	store, _ := newTestSQLiteStore(t, 10)
+	testStoreQuery(t, store)
+}
+
+func TestFileStoreQuery(t *testing.T) {
+	testStoreQuery(t, newTestFileStore(t, filepath.Join(t.TempDir(), "events"), FileOptions{Capacity: 10}))
+}
+
+func TestFileStoreQueryCursorSurvivesCompaction(t *testing.T) {
+	store := newTestFileStore(t, t.TempDir(), FileOptions{Capacity: 100, SegmentSize: 1 << 10})
+	for i := 0; i < 4; i++ {
+		store.Save(makeEvent("ch"))
+	}
+	first, _ := store.Query(Query{Limit: 2})
+
+	// Force several rotations and compactions.
+	for i := 0; i < 20; i++ {
+		store.Save(makeEvent("other"))
+	}
+
+	next, err := store.Query(Query{ChannelID: "ch", Cursor: first.NextCursor})
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	if len(next.Events) != 2 {
+		t.Fatalf("page after compaction = %d events, want 2", len(next.Events))
+	}
+	if next.Events[0].ID == first.Events[1].ID {
+		t.Error("page after compaction repeated an event from the previous page")
+	}
+}
@@ -0,0 +1,446 @@
+package event
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/youmna-rabie/claude-pod/internal/types"
+
+	_ "modernc.org/sqlite" // registers the pure-Go "sqlite" driver
+)
+
+// migrations are applied in order to bring a database up to the current
+// schema. The index of each entry plus one is its schema version, tracked in
+// SQLite's user_version pragma. Append new migrations; never edit old ones.
+var migrations = []string{
+	`CREATE TABLE events (
+		seq        INTEGER PRIMARY KEY AUTOINCREMENT,
+		id         TEXT    NOT NULL UNIQUE,
+		channel_id TEXT    NOT NULL,
+		raw_body   BLOB,
+		headers    TEXT    NOT NULL DEFAULT '{}',
+		timestamp  INTEGER NOT NULL,
+		status     TEXT    NOT NULL,
+		attempts   INTEGER NOT NULL DEFAULT 0
+	);
+	CREATE INDEX idx_events_channel_id ON events(channel_id);
+	CREATE INDEX idx_events_timestamp ON events(timestamp);
+	CREATE INDEX idx_events_status ON events(status);`,
+
+	`CREATE TABLE event_history (
+		seq       INTEGER PRIMARY KEY AUTOINCREMENT,
+		event_id  TEXT    NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+		status    TEXT    NOT NULL,
+		timestamp INTEGER NOT NULL,
+		error     TEXT    NOT NULL DEFAULT '',
+		attempt   INTEGER NOT NULL DEFAULT 0,
+		response  BLOB
+	);
+	CREATE INDEX idx_event_history_event_id ON event_history(event_id);`,
+
+	`ALTER TABLE events ADD COLUMN metadata TEXT NOT NULL DEFAULT '{}';`,
+
+	`ALTER TABLE event_history ADD COLUMN replay INTEGER NOT NULL DEFAULT 0;`,
+}
+
+// SQLiteStore is a persistent event store backed by a SQLite database.
+// Like MemoryStore it keeps at most capacity events, evicting the oldest
+// on Save, and lists events newest-first in insertion order.
+type SQLiteStore struct {
+	db  *sql.DB
+	cap int
+}
+
+// NewSQLiteStore opens (or creates) the database at path, migrates it to the
+// current schema, and returns a store holding at most capacity events.
+func NewSQLiteStore(path string, capacity int) (*SQLiteStore, error) {
+	if capacity <= 0 {
+		return nil, ErrInvalidCapacity
+	}
+
+	// Foreign keys let evicting an event cascade to its history.
+	dsn := "file:" + path + "?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_pragma=foreign_keys(1)"
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("opening sqlite %s: %w", path, err)
+	}
+	// SQLite allows a single writer; serializing access avoids SQLITE_BUSY.
+	db.SetMaxOpenConns(1)
+
+	if err := migrate(db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("migrating sqlite %s: %w", path, err)
+	}
+
+	return &SQLiteStore{db: db, cap: capacity}, nil
+}
+
+// migrate applies any migrations newer than the database's user_version.
+func migrate(db *sql.DB) error {
+	var version int
+	if err := db.QueryRow(`PRAGMA user_version`).Scan(&version); err != nil {
+		return err
+	}
+
+	for v := version; v < len(migrations); v++ {
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(migrations[v]); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migration %d: %w", v+1, err)
+		}
+		// PRAGMA does not accept bound parameters.
+		if _, err := tx.Exec(fmt.Sprintf(`PRAGMA user_version = %d`, v+1)); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migration %d: %w", v+1, err)
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("migration %d: %w", v+1, err)
+		}
+	}
+	return nil
+}
+
+// Close closes the underlying database.
+func (s *SQLiteStore) Close() error {
+	return s.db.Close()
+}
+
+// Save inserts an event and its initial history entry. If the store is at
+// capacity, the oldest events and their history are evicted.
+func (s *SQLiteStore) Save(event types.Event) error {
+	headers, err := json.Marshal(event.Headers)
+	if err != nil {
+		return fmt.Errorf("encoding headers: %w", err)
+	}
+	metadata, err := json.Marshal(event.Metadata)
+	if err != nil {
+		return fmt.Errorf("encoding metadata: %w", err)
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(
+		`INSERT INTO events (id, channel_id, raw_body, headers, timestamp, status, attempts, metadata)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		event.ID.String(), event.ChannelID, []byte(event.RawBody), string(headers),
+		event.Timestamp.UnixNano(), string(event.Status), event.Attempts, string(metadata),
+	)
+	if err != nil {
+		return fmt.Errorf("inserting event: %w", err)
+	}
+	if err := insertChange(tx, event.ID, types.StatusChange{Status: event.Status, Timestamp: event.Timestamp}); err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(
+		`DELETE FROM events WHERE seq <= (SELECT seq FROM events ORDER BY seq DESC LIMIT 1 OFFSET ?)`,
+		s.cap,
+	)
+	if err != nil {
+		return fmt.Errorf("evicting events: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// Get retrieves an event by ID.
+func (s *SQLiteStore) Get(id uuid.UUID) (types.Event, error) {
+	row := s.db.QueryRow(
+		`SELECT id, channel_id, raw_body, headers, timestamp, status, attempts, metadata
+		 FROM events WHERE id = ?`,
+		id.String(),
+	)
+	event, err := scanEvent(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return types.Event{}, ErrNotFound
+	}
+	return event, err
+}
+
+// List returns up to limit events ordered newest-first, skipping the first offset results.
+func (s *SQLiteStore) List(limit, offset int) ([]types.Event, error) {
+	if limit <= 0 {
+		return nil, nil
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	rows, err := s.db.Query(
+		`SELECT id, channel_id, raw_body, headers, timestamp, status, attempts, metadata
+		 FROM events ORDER BY seq DESC LIMIT ? OFFSET ?`,
+		limit, offset,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("listing events: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]types.Event, 0, limit)
+	for rows.Next() {
+		event, err := scanEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, event)
+	}
+	return result, rows.Err()
+}
+
+// Query returns one page of events matching q, newest-first. Channel,
+// status, time, body and cursor filters run in SQL; header filters are
+// applied while scanning since headers are stored as encoded JSON.
+func (s *SQLiteStore) Query(q Query) (Page, error) {
+	before, err := decodeCursor(q.Cursor)
+	if err != nil {
+		return Page{}, err
+	}
+	limit := q.limit()
+
+	var (
+		where []string
+		args  []any
+	)
+	if before != 0 {
+		where, args = append(where, "seq < ?"), append(args, before)
+	}
+	if q.ChannelID != "" {
+		where, args = append(where, "channel_id = ?"), append(args, q.ChannelID)
+	}
+	if q.Status != "" {
+		where, args = append(where, "status = ?"), append(args, string(q.Status))
+	}
+	if !q.Since.IsZero() {
+		where, args = append(where, "timestamp >= ?"), append(args, q.Since.UnixNano())
+	}
+	if !q.Until.IsZero() {
+		where, args = append(where, "timestamp < ?"), append(args, q.Until.UnixNano())
+	}
+	if q.BodyContains != "" {
+		where, args = append(where, "instr(raw_body, ?) > 0"), append(args, []byte(q.BodyContains))
+	}
+
+	query := `SELECT seq, id, channel_id, raw_body, headers, timestamp, status, attempts, metadata FROM events`
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY seq DESC"
+	if q.HeaderContains == "" {
+		// One extra row tells us whether another page exists.
+		query += " LIMIT ?"
+		args = append(args, limit+1)
+	}
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return Page{}, fmt.Errorf("querying events: %w", err)
+	}
+	defer rows.Close()
+
+	page := Page{Events: []types.Event{}}
+	var last uint64
+	for rows.Next() {
+		var seq uint64
+		event, err := scanEvent(rows, &seq)
+		if err != nil {
+			return Page{}, err
+		}
+		if !q.Match(event) {
+			continue
+		}
+		if len(page.Events) == limit {
+			page.NextCursor = encodeCursor(last)
+			break
+		}
+		page.Events = append(page.Events, event)
+		last = seq
+	}
+	return page, rows.Err()
+}
+
+// UpdateStatus changes the status of an event identified by ID and records it in the history.
+func (s *SQLiteStore) UpdateStatus(id uuid.UUID, status types.EventStatus) error {
+	return s.Transition(id, types.StatusChange{Status: status})
+}
+
+// Transition changes an event's status and appends change to its history.
+func (s *SQLiteStore) Transition(id uuid.UUID, change types.StatusChange) error {
+	if change.Timestamp.IsZero() {
+		change.Timestamp = time.Now()
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(
+		`UPDATE events SET status = ?, attempts = CASE WHEN ? > 0 THEN ? ELSE attempts END WHERE id = ?`,
+		string(change.Status), change.Attempt, change.Attempt, id.String(),
+	)
+	if err != nil {
+		return fmt.Errorf("updating event: %w", err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return ErrNotFound
+	}
+	if err := insertChange(tx, id, change); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// History returns an event's status changes, oldest first.
+func (s *SQLiteStore) History(id uuid.UUID) ([]types.StatusChange, error) {
+	var exists bool
+	if err := s.db.QueryRow(`SELECT EXISTS (SELECT 1 FROM events WHERE id = ?)`, id.String()).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("looking up event: %w", err)
+	}
+	if !exists {
+		return nil, ErrNotFound
+	}
+
+	rows, err := s.db.Query(
+		`SELECT status, timestamp, error, attempt, response, replay
+		 FROM event_history WHERE event_id = ? ORDER BY seq`,
+		id.String(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("listing history: %w", err)
+	}
+	defer rows.Close()
+
+	history := []types.StatusChange{}
+	for rows.Next() {
+		var (
+			change    types.StatusChange
+			status    string
+			timestamp int64
+			response  []byte
+		)
+		if err := rows.Scan(&status, &timestamp, &change.Error, &change.Attempt, &response, &change.Replay); err != nil {
+			return nil, err
+		}
+		change.Status = types.EventStatus(status)
+		change.Timestamp = time.Unix(0, timestamp)
+		if len(response) > 0 {
+			change.Response = json.RawMessage(response)
+		}
+		history = append(history, change)
+	}
+	return history, rows.Err()
+}
+
+// UpdateAttempts records the number of forward attempts for an event identified by ID.
+func (s *SQLiteStore) UpdateAttempts(id uuid.UUID, attempts int) error {
+	return s.update(`UPDATE events SET attempts = ? WHERE id = ?`, attempts, id.String())
+}
+
+// Delete removes an event; its history is removed by cascade.
+func (s *SQLiteStore) Delete(id uuid.UUID) error {
+	res, err := s.db.Exec(`DELETE FROM events WHERE id = ?`, id.String())
+	if err != nil {
+		return fmt.Errorf("deleting event: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Count returns the number of events currently stored, or 0 if the query fails.
+func (s *SQLiteStore) Count() int {
+	var n int
+	if err := s.db.QueryRow(`SELECT COUNT(*) FROM events`).Scan(&n); err != nil {
+		return 0
+	}
+	return n
+}
+
+// update runs a single-row UPDATE and maps "no rows affected" to ErrNotFound.
+func (s *SQLiteStore) update(query string, args ...any) error {
+	res, err := s.db.Exec(query, args...)
+	if err != nil {
+		return fmt.Errorf("updating event: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// insertChange appends a history entry for an event inside tx.
+func insertChange(tx *sql.Tx, id uuid.UUID, change types.StatusChange) error {
+	_, err := tx.Exec(
+		`INSERT INTO event_history (event_id, status, timestamp, error, attempt, response, replay)
+		 VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		id.String(), string(change.Status), change.Timestamp.UnixNano(),
+		change.Error, change.Attempt, []byte(change.Response), change.Replay,
+	)
+	if err != nil {
+		return fmt.Errorf("recording history: %w", err)
+	}
+	return nil
+}
+
+// scanner is implemented by *sql.Row and *sql.Rows.
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+// scanEvent decodes an event row. Any extra destinations are scanned from
+// leading columns selected before the event columns.
+func scanEvent(row scanner, extra ...any) (types.Event, error) {
+	var (
+		id, channelID, headers, status, metadata string
+		rawBody                                  []byte
+		timestamp                                int64
+		attempts                                 int
+	)
+	dest := append(extra, &id, &channelID, &rawBody, &headers, &timestamp, &status, &attempts, &metadata)
+	if err := row.Scan(dest...); err != nil {
+		return types.Event{}, err
+	}
+
+	event := types.Event{
+		ChannelID: channelID,
+		RawBody:   json.RawMessage(rawBody),
+		Timestamp: time.Unix(0, timestamp),
+		Status:    types.EventStatus(status),
+		Attempts:  attempts,
+	}
+	var err error
+	if event.ID, err = uuid.Parse(id); err != nil {
+		return types.Event{}, fmt.Errorf("parsing event id %q: %w", id, err)
+	}
+	if err := json.Unmarshal([]byte(headers), &event.Headers); err != nil {
+		return types.Event{}, fmt.Errorf("decoding headers of event %s: %w", id, err)
+	}
+	if err := json.Unmarshal([]byte(metadata), &event.Metadata); err != nil {
+		return types.Event{}, fmt.Errorf("decoding metadata of event %s: %w", id, err)
+	}
+	return event, nil
+}
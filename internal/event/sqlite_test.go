@@ -0,0 +1,243 @@
+package event
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/youmna-rabie/claude-pod/internal/types"
+)
+
+func newTestSQLiteStore(t *testing.T, capacity int) (*SQLiteStore, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "events.db")
+	store, err := NewSQLiteStore(path, capacity)
+	if err != nil {
+		t.Fatalf("NewSQLiteStore: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store, path
+}
+
+func TestNewSQLiteStore_InvalidCapacity(t *testing.T) {
+	_, err := NewSQLiteStore(filepath.Join(t.TempDir(), "events.db"), 0)
+	if err != ErrInvalidCapacity {
+		t.Errorf("error = %v, want ErrInvalidCapacity", err)
+	}
+}
+
+func TestSQLiteSaveAndGet(t *testing.T) {
+	store, _ := newTestSQLiteStore(t, 10)
+
+	ev := makeEvent("slack")
+	if err := store.Save(ev); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	got, err := store.Get(ev.ID)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.ID != ev.ID || got.ChannelID != ev.ChannelID || got.Status != ev.Status {
+		t.Errorf("Get = %+v, want %+v", got, ev)
+	}
+	if string(got.RawBody) != string(ev.RawBody) {
+		t.Errorf("RawBody = %s, want %s", got.RawBody, ev.RawBody)
+	}
+	if got.Headers["X-Test"] != "1" {
+		t.Errorf("Headers = %v, want X-Test=1", got.Headers)
+	}
+	if !got.Timestamp.Equal(ev.Timestamp) {
+		t.Errorf("Timestamp = %v, want %v", got.Timestamp, ev.Timestamp)
+	}
+}
+
+func TestSQLiteSavesMetadata(t *testing.T) {
+	store, _ := newTestSQLiteStore(t, 10)
+
+	ev := makeEvent("slack")
+	ev.Metadata = map[string]string{"redactions": "2"}
+	if err := store.Save(ev); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	plain := makeEvent("slack")
+	if err := store.Save(plain); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	got, err := store.Get(ev.ID)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.Metadata["redactions"] != "2" {
+		t.Errorf("Metadata = %v, want redactions=2", got.Metadata)
+	}
+	if got, _ := store.Get(plain.ID); len(got.Metadata) != 0 {
+		t.Errorf("Metadata of plain event = %v, want none", got.Metadata)
+	}
+}
+
+func TestSQLiteGetNotFound(t *testing.T) {
+	store, _ := newTestSQLiteStore(t, 10)
+	if _, err := store.Get(uuid.New()); err != ErrNotFound {
+		t.Errorf("Get unknown ID: error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestSQLiteEvictionAtCapacity(t *testing.T) {
+	store, _ := newTestSQLiteStore(t, 3)
+
+	events := make([]types.Event, 5)
+	for i := range events {
+		events[i] = makeEvent("ch")
+		if err := store.Save(events[i]); err != nil {
+			t.Fatalf("Save[%d]: %v", i, err)
+		}
+	}
+
+	if c := store.Count(); c != 3 {
+		t.Errorf("Count = %d, want 3", c)
+	}
+	for _, ev := range events[:2] {
+		if _, err := store.Get(ev.ID); err != ErrNotFound {
+			t.Errorf("Get evicted event %v: error = %v, want ErrNotFound", ev.ID, err)
+		}
+	}
+	for _, ev := range events[2:] {
+		if _, err := store.Get(ev.ID); err != nil {
+			t.Errorf("Get retained event %v: %v", ev.ID, err)
+		}
+	}
+}
+
+func TestSQLiteListPagination(t *testing.T) {
+	store, _ := newTestSQLiteStore(t, 10)
+
+	events := make([]types.Event, 5)
+	for i := range events {
+		events[i] = makeEvent("ch")
+		store.Save(events[i])
+	}
+
+	all, err := store.List(10, 0)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(all) != 5 {
+		t.Fatalf("List len = %d, want 5", len(all))
+	}
+	for i, ev := range all {
+		if want := events[len(events)-1-i]; ev.ID != want.ID {
+			t.Errorf("List[%d].ID = %v, want %v", i, ev.ID, want.ID)
+		}
+	}
+
+	page2, _ := store.List(2, 2)
+	if len(page2) != 2 || page2[0].ID != events[2].ID || page2[1].ID != events[1].ID {
+		t.Error("page2 has wrong events")
+	}
+
+	if empty, _ := store.List(2, 10); len(empty) != 0 {
+		t.Errorf("List beyond range len = %d, want 0", len(empty))
+	}
+	if none, _ := store.List(0, 0); none != nil {
+		t.Errorf("List(0, 0) = %v, want nil", none)
+	}
+}
+
+func TestSQLiteUpdateStatusAndAttempts(t *testing.T) {
+	store, _ := newTestSQLiteStore(t, 10)
+
+	ev := makeEvent("slack")
+	store.Save(ev)
+
+	if err := store.UpdateStatus(ev.ID, types.EventStatusForwarded); err != nil {
+		t.Fatalf("UpdateStatus: %v", err)
+	}
+	if err := store.UpdateAttempts(ev.ID, 2); err != nil {
+		t.Fatalf("UpdateAttempts: %v", err)
+	}
+
+	got, _ := store.Get(ev.ID)
+	if got.Status != types.EventStatusForwarded {
+		t.Errorf("Status = %q, want %q", got.Status, types.EventStatusForwarded)
+	}
+	if got.Attempts != 2 {
+		t.Errorf("Attempts = %d, want 2", got.Attempts)
+	}
+
+	if err := store.UpdateStatus(uuid.New(), types.EventStatusFailed); err != ErrNotFound {
+		t.Errorf("UpdateStatus unknown ID: error = %v, want ErrNotFound", err)
+	}
+	if err := store.UpdateAttempts(uuid.New(), 1); err != ErrNotFound {
+		t.Errorf("UpdateAttempts unknown ID: error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestSQLiteDelete(t *testing.T) {
+	store, _ := newTestSQLiteStore(t, 10)
+	ev := makeEvent("ch")
+	store.Save(ev)
+
+	if err := store.Delete(ev.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := store.Get(ev.ID); err != ErrNotFound {
+		t.Errorf("Get deleted event: error = %v, want ErrNotFound", err)
+	}
+	var rows int
+	store.db.QueryRow(`SELECT COUNT(*) FROM event_history WHERE event_id = ?`, ev.ID.String()).Scan(&rows)
+	if rows != 0 {
+		t.Errorf("deleted event left %d history rows, want 0", rows)
+	}
+	if err := store.Delete(ev.ID); err != ErrNotFound {
+		t.Errorf("Delete twice: error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestSQLitePersistsAcrossReopen(t *testing.T) {
+	store, path := newTestSQLiteStore(t, 10)
+
+	ev := makeEvent("grafana")
+	store.Save(ev)
+	store.UpdateStatus(ev.ID, types.EventStatusFailed)
+	store.Close()
+
+	reopened, err := NewSQLiteStore(path, 10)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	defer reopened.Close()
+
+	got, err := reopened.Get(ev.ID)
+	if err != nil {
+		t.Fatalf("Get after reopen: %v", err)
+	}
+	if got.Status != types.EventStatusFailed {
+		t.Errorf("Status after reopen = %q, want %q", got.Status, types.EventStatusFailed)
+	}
+	if c := reopened.Count(); c != 1 {
+		t.Errorf("Count after reopen = %d, want 1", c)
+	}
+}
+
+func TestSQLiteMigrationsRecordVersion(t *testing.T) {
+	store, _ := newTestSQLiteStore(t, 10)
+
+	var version int
+	if err := store.db.QueryRow(`PRAGMA user_version`).Scan(&version); err != nil {
+		t.Fatal(err)
+	}
+	if version != len(migrations) {
+		t.Errorf("user_version = %d, want %d", version, len(migrations))
+	}
+
+	// Migrating an up-to-date database is a no-op.
+	if err := migrate(store.db); err != nil {
+		t.Errorf("re-running migrate: %v", err)
+	}
+}
+
+func TestSQLiteStoreInterface(t *testing.T) {
+	var _ Store = (*SQLiteStore)(nil)
+}
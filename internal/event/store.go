@@ -7,7 +7,8 @@ import (
 
 // Store defines the interface for persisting and querying events.
 type Store interface {
-	// Save persists an event. Returns an error if the store is closed or the event is invalid.
+	// Save persists an event and starts its history with its initial status.
+	// Returns an error if the store is closed or the event is invalid.
 	Save(event types.Event) error
 
 	// Get retrieves an event by ID. Returns an error if not found.
@@ -17,10 +18,31 @@ type Store interface {
 	// offset skips the first N results for pagination.
 	List(limit, offset int) ([]types.Event, error)
 
-	// UpdateStatus changes the status of an event identified by ID.
-	// Returns an error if the event is not found.
+	// Query returns one page of events matching q, ordered newest-first.
+	// Returns ErrInvalidCursor if q.Cursor is not a cursor from a previous page.
+	Query(q Query) (Page, error)
+
+	// UpdateStatus changes the status of an event identified by ID and
+	// records the change in its history. Returns an error if the event is not found.
 	UpdateStatus(id uuid.UUID, status types.EventStatus) error
 
+	// Transition changes an event's status and appends change to its history.
+	// A zero change.Timestamp means now; a positive change.Attempt also
+	// updates the event's attempt count. Returns an error if the event is not found.
+	Transition(id uuid.UUID, change types.StatusChange) error
+
+	// History returns an event's status changes, oldest first.
+	// Returns an error if the event is not found.
+	History(id uuid.UUID) ([]types.StatusChange, error)
+
+	// UpdateAttempts records how many forward attempts were made for an event.
+	// Returns an error if the event is not found.
+	UpdateAttempts(id uuid.UUID, attempts int) error
+
+	// Delete removes an event and its history.
+	// Returns an error if the event is not found.
+	Delete(id uuid.UUID) error
+
 	// Count returns the total number of events currently stored.
 	Count() int
 }
@@ -0,0 +1,193 @@
+// Package metrics defines the gateway's Prometheus metrics and serves them
+// in the text exposition format.
+//
+// All recording methods are safe to call on a nil *Metrics, so instrumented
+// code does not need to check whether metrics are enabled.
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/youmna-rabie/claude-pod/internal/agent"
+)
+
+const namespace = "gateway"
+
+// Metrics holds the gateway's collectors in a private registry.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	httpRequests *prometheus.CounterVec
+	httpDuration *prometheus.HistogramVec
+	httpInFlight prometheus.Gauge
+
+	webhooks        *prometheus.CounterVec
+	webhookRejects  *prometheus.CounterVec
+	forwardDuration *prometheus.HistogramVec
+	forwardErrors   *prometheus.CounterVec
+}
+
+// New creates Metrics with every gateway collector registered, plus the
+// standard Go runtime and process collectors.
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "HTTP requests handled, by method, route pattern and status code.",
+		}, []string{"method", "route", "code"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Time to handle HTTP requests, by method and route pattern.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+		httpInFlight: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "http_requests_in_flight",
+			Help:      "HTTP requests currently being handled.",
+		}),
+		webhooks: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "webhook_requests_total",
+			Help:      "Webhook requests, by channel and response status code.",
+		}, []string{"channel", "code"}),
+		webhookRejects: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "webhook_rejected_total",
+			Help:      "Webhook requests rejected before storing, by channel and stage (rate_limit, validation or parse).",
+		}, []string{"channel", "stage"}),
+		forwardDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "agent_forward_duration_seconds",
+			Help:      "Time to forward an event to the agent, including retries, by channel and outcome.",
+			Buckets:   []float64{.01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60},
+		}, []string{"channel", "outcome"}),
+		forwardErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "agent_forward_errors_total",
+			Help:      "Failed agent forwards, by channel and reason.",
+		}, []string{"channel", "reason"}),
+	}
+
+	m.registry.MustRegister(
+		m.httpRequests, m.httpDuration, m.httpInFlight,
+		m.webhooks, m.webhookRejects, m.forwardDuration, m.forwardErrors,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return m
+}
+
+// Handler serves the registered metrics in the Prometheus text format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// storeStats is the part of an event store the size gauge reads.
+type storeStats interface {
+	Count() int
+}
+
+// evictionCounter is implemented by stores that drop old events when full.
+type evictionCounter interface {
+	Evictions() uint64
+}
+
+// RegisterStore exports the size of store, and its evictions if it counts
+// them, labelled with name (for example "events" or "dead_letter").
+func (m *Metrics) RegisterStore(name string, store storeStats) {
+	if m == nil {
+		return
+	}
+	labels := prometheus.Labels{"store": name}
+	m.registry.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace:   namespace,
+		Name:        "store_events",
+		Help:        "Events currently held by the store.",
+		ConstLabels: labels,
+	}, func() float64 { return float64(store.Count()) }))
+
+	if ec, ok := store.(evictionCounter); ok {
+		m.registry.MustRegister(prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Namespace:   namespace,
+			Name:        "store_evictions_total",
+			Help:        "Events dropped from the store to make room for newer ones.",
+			ConstLabels: labels,
+		}, func() float64 { return float64(ec.Evictions()) }))
+	}
+}
+
+// RequestStarted marks an HTTP request as in flight.
+func (m *Metrics) RequestStarted() {
+	if m == nil {
+		return
+	}
+	m.httpInFlight.Inc()
+}
+
+// RequestFinished records a handled HTTP request. route is the matched
+// route pattern rather than the raw path, keeping label values bounded.
+func (m *Metrics) RequestFinished(method, route string, code int, d time.Duration) {
+	if m == nil {
+		return
+	}
+	m.httpInFlight.Dec()
+	m.httpRequests.WithLabelValues(method, route, strconv.Itoa(code)).Inc()
+	m.httpDuration.WithLabelValues(method, route).Observe(d.Seconds())
+}
+
+// Webhook records the response code of a webhook request.
+func (m *Metrics) Webhook(channel string, code int) {
+	if m == nil {
+		return
+	}
+	m.webhooks.WithLabelValues(channel, strconv.Itoa(code)).Inc()
+}
+
+// WebhookRejected records a webhook that was rate limited or failed
+// validation or parsing.
+func (m *Metrics) WebhookRejected(channel, stage string) {
+	if m == nil {
+		return
+	}
+	m.webhookRejects.WithLabelValues(channel, stage).Inc()
+}
+
+// Forward records one agent forward and, if it failed, why.
+func (m *Metrics) Forward(channel string, d time.Duration, err error) {
+	if m == nil {
+		return
+	}
+	outcome := "success"
+	if err != nil {
+		outcome = "error"
+		m.forwardErrors.WithLabelValues(channel, forwardErrorReason(err)).Inc()
+	}
+	m.forwardDuration.WithLabelValues(channel, outcome).Observe(d.Seconds())
+}
+
+// forwardErrorReason classifies a forward error for the reason label.
+func forwardErrorReason(err error) string {
+	var statusErr *agent.StatusError
+	switch {
+	case errors.Is(err, agent.ErrCircuitOpen):
+		return "circuit_open"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.As(err, &statusErr):
+		return "agent_status"
+	default:
+		return "transport"
+	}
+}
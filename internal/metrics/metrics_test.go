@@ -0,0 +1,73 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/youmna-rabie/claude-pod/internal/agent"
+)
+
+func TestNilMetricsIsNoop(t *testing.T) {
+	var m *Metrics
+	m.RegisterStore("events", fakeStore{})
+	m.RequestStarted()
+	m.RequestFinished(http.MethodGet, "/health", http.StatusOK, time.Millisecond)
+	m.Webhook("grafana", http.StatusOK)
+	m.WebhookRejected("grafana", "validation")
+	m.Forward("grafana", time.Millisecond, errors.New("boom"))
+}
+
+func TestForwardErrorReason(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{agent.ErrCircuitOpen, "circuit_open"},
+		{fmt.Errorf("attempt 3: %w", context.DeadlineExceeded), "timeout"},
+		{context.Canceled, "canceled"},
+		{&agent.StatusError{StatusCode: http.StatusBadGateway}, "agent_status"},
+		{errors.New("connection refused"), "transport"},
+	}
+	for _, tt := range tests {
+		if got := forwardErrorReason(tt.err); got != tt.want {
+			t.Errorf("forwardErrorReason(%v) = %q, want %q", tt.err, got, tt.want)
+		}
+	}
+}
+
+type fakeStore struct{ count int }
+
+func (s fakeStore) Count() int { return s.count }
+
+type evictingStore struct{ fakeStore }
+
+func (evictingStore) Evictions() uint64 { return 7 }
+
+func TestRegisterStore(t *testing.T) {
+	m := New()
+	m.RegisterStore("events", evictingStore{fakeStore{count: 3}})
+	m.RegisterStore("dead_letter", fakeStore{count: 1})
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := rec.Body.String()
+
+	for _, want := range []string{
+		`gateway_store_events{store="events"} 3`,
+		`gateway_store_evictions_total{store="events"} 7`,
+		`gateway_store_events{store="dead_letter"} 1`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics missing %s", want)
+		}
+	}
+	if strings.Contains(body, `gateway_store_evictions_total{store="dead_letter"}`) {
+		t.Error("evictions exported for a store that does not count them")
+	}
+}
@@ -0,0 +1,166 @@
+package redact
+
+import (
+	"bytes"
+	"encoding/json"
+	"maps"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/youmna-rabie/claude-pod/internal/config"
+	"github.com/youmna-rabie/claude-pod/internal/types"
+)
+
+// MetadataKey is the event metadata key holding how many values were
+// redacted from that copy of the event's body.
+const MetadataKey = "redactions"
+
+// BodyPolicy redacts values from event bodies by JSON path and by regular
+// expression. A nil *BodyPolicy redacts nothing.
+type BodyPolicy struct {
+	paths    [][]string
+	patterns []*regexp.Regexp
+}
+
+// NewBodyPolicy builds a policy from rules, or returns nil if rules are
+// empty. The patterns must compile, which config.Load checks.
+func NewBodyPolicy(rules config.RedactRules) *BodyPolicy {
+	if len(rules.Paths) == 0 && len(rules.Patterns) == 0 {
+		return nil
+	}
+	p := &BodyPolicy{}
+	for _, path := range rules.Paths {
+		p.paths = append(p.paths, splitPath(path))
+	}
+	for _, pattern := range rules.Patterns {
+		p.patterns = append(p.patterns, regexp.MustCompile(pattern))
+	}
+	return p
+}
+
+// splitPath turns "$.alerts[*].labels.email" or "alerts.*.labels.email"
+// into its segments. A * segment matches every key or element, and a
+// number also matches that array index.
+func splitPath(path string) []string {
+	path = strings.TrimPrefix(strings.TrimPrefix(path, "$"), ".")
+	path = strings.ReplaceAll(path, "[", ".")
+	path = strings.ReplaceAll(path, "]", "")
+	return strings.Split(path, ".")
+}
+
+// ApplyEvent returns evt with its body redacted and the count recorded in
+// its metadata, added to any count already there. evt is not modified.
+func (p *BodyPolicy) ApplyEvent(evt types.Event) types.Event {
+	if p == nil {
+		return evt
+	}
+	body, n := p.Apply(evt.RawBody)
+	if prev, err := strconv.Atoi(evt.Metadata[MetadataKey]); err == nil {
+		n += prev
+	}
+	evt.RawBody = body
+	evt.Metadata = maps.Clone(evt.Metadata)
+	if evt.Metadata == nil {
+		evt.Metadata = make(map[string]string, 1)
+	}
+	evt.Metadata[MetadataKey] = strconv.Itoa(n)
+	return evt
+}
+
+// Apply returns body with every value matching a path replaced by
+// Redacted, and every pattern match within string values replaced by
+// Redacted, along with how many replacements were made. A body that is not
+// JSON only has the patterns applied to its raw text. body is not modified.
+func (p *BodyPolicy) Apply(body json.RawMessage) (json.RawMessage, int) {
+	if p == nil || len(body) == 0 {
+		return body, 0
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var doc any
+	if err := dec.Decode(&doc); err != nil || dec.More() {
+		return p.applyText(body)
+	}
+
+	n := 0
+	for _, path := range p.paths {
+		doc = redactPath(doc, path, &n)
+	}
+	if len(p.patterns) > 0 {
+		doc = p.redactStrings(doc, &n)
+	}
+	if n == 0 {
+		return body, 0
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(doc); err != nil {
+		return body, 0
+	}
+	return json.RawMessage(bytes.TrimSuffix(buf.Bytes(), []byte("\n"))), n
+}
+
+func (p *BodyPolicy) applyText(body json.RawMessage) (json.RawMessage, int) {
+	text, n := p.replace(string(body))
+	return json.RawMessage(text), n
+}
+
+// replace substitutes every pattern match in s.
+func (p *BodyPolicy) replace(s string) (string, int) {
+	n := 0
+	for _, re := range p.patterns {
+		s = re.ReplaceAllStringFunc(s, func(string) string {
+			n++
+			return Redacted
+		})
+	}
+	return s, n
+}
+
+// redactPath replaces the values at path within v, counting them in n.
+func redactPath(v any, path []string, n *int) any {
+	if len(path) == 0 {
+		*n++
+		return Redacted
+	}
+	seg, rest := path[0], path[1:]
+	switch node := v.(type) {
+	case map[string]any:
+		for key, child := range node {
+			if seg == "*" || seg == key {
+				node[key] = redactPath(child, rest, n)
+			}
+		}
+	case []any:
+		idx, err := strconv.Atoi(seg)
+		for i, child := range node {
+			if seg == "*" || (err == nil && i == idx) {
+				node[i] = redactPath(child, rest, n)
+			}
+		}
+	}
+	return v
+}
+
+// redactStrings applies the patterns to every string value within v.
+func (p *BodyPolicy) redactStrings(v any, n *int) any {
+	switch node := v.(type) {
+	case string:
+		s, count := p.replace(node)
+		*n += count
+		return s
+	case map[string]any:
+		for key, child := range node {
+			node[key] = p.redactStrings(child, n)
+		}
+	case []any:
+		for i, child := range node {
+			node[i] = p.redactStrings(child, n)
+		}
+	}
+	return v
+}
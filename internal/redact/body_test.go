@@ -0,0 +1,99 @@
+package redact
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/youmna-rabie/claude-pod/internal/config"
+	"github.com/youmna-rabie/claude-pod/internal/types"
+)
+
+const alertBody = `{"alerts":[{"labels":{"alertname":"HighCPU","customer_id":"c-1"},"annotations":{"summary":"page ops@example.com"}},` +
+	`{"labels":{"alertname":"Disk","customer_id":"c-2"}}],"token":"abc","note":"<b>&</b>"}`
+
+func TestBodyPolicyPaths(t *testing.T) {
+	policy := NewBodyPolicy(config.RedactRules{Paths: []string{"$.alerts[*].labels.customer_id", "token", "alerts.1"}})
+	got, n := policy.Apply(json.RawMessage(alertBody))
+
+	var doc struct {
+		Alerts []any  `json:"alerts"`
+		Token  string `json:"token"`
+		Note   string `json:"note"`
+	}
+	if err := json.Unmarshal(got, &doc); err != nil {
+		t.Fatalf("redacted body is not JSON: %v\n%s", err, got)
+	}
+	first := doc.Alerts[0].(map[string]any)["labels"].(map[string]any)
+	if first["customer_id"] != Redacted || first["alertname"] != "HighCPU" {
+		t.Errorf("alerts[0].labels = %v", first)
+	}
+	if doc.Alerts[1] != Redacted || doc.Token != Redacted {
+		t.Errorf("alerts[1] = %v, token = %v, want both redacted", doc.Alerts[1], doc.Token)
+	}
+	if doc.Note != "<b>&</b>" {
+		t.Errorf("note = %q, want unchanged", doc.Note)
+	}
+	// customer_id in both alerts, token, then alerts[1] as a whole.
+	if n != 4 {
+		t.Errorf("count = %d, want 4", n)
+	}
+}
+
+func TestBodyPolicyPatterns(t *testing.T) {
+	policy := NewBodyPolicy(config.RedactRules{Patterns: []string{`[\w.+-]+@[\w-]+\.[\w.]+`, `c-\d+`}})
+
+	got, n := policy.Apply(json.RawMessage(alertBody))
+	if n != 3 {
+		t.Errorf("count = %d, want 3 (one email, two customer IDs)", n)
+	}
+	var doc map[string]any
+	if err := json.Unmarshal(got, &doc); err != nil {
+		t.Fatal(err)
+	}
+	summary := doc["alerts"].([]any)[0].(map[string]any)["annotations"].(map[string]any)["summary"]
+	if summary != "page "+Redacted {
+		t.Errorf("summary = %q", summary)
+	}
+
+	text, n := policy.Apply(json.RawMessage("mail ops@example.com now"))
+	if string(text) != "mail "+Redacted+" now" || n != 1 {
+		t.Errorf("non-JSON body = %q (%d), want the email redacted", text, n)
+	}
+}
+
+func TestBodyPolicyNoMatchKeepsBody(t *testing.T) {
+	body := json.RawMessage(`{ "b": 1, "a": 2 }`)
+	got, n := NewBodyPolicy(config.RedactRules{Paths: []string{"missing"}}).Apply(body)
+	if n != 0 || string(got) != string(body) {
+		t.Errorf("Apply = %s (%d), want the body untouched", got, n)
+	}
+	if NewBodyPolicy(config.RedactRules{}) != nil {
+		t.Error("NewBodyPolicy of empty rules is not nil")
+	}
+}
+
+func TestBodyPolicyApplyEvent(t *testing.T) {
+	evt := types.Event{
+		ID:       uuid.New(),
+		RawBody:  json.RawMessage(`{"email":"a@b.co","token":"t"}`),
+		Metadata: map[string]string{"source": "test"},
+	}
+	stored := NewBodyPolicy(config.RedactRules{Paths: []string{"email"}}).ApplyEvent(evt)
+	if stored.Metadata[MetadataKey] != "1" || stored.Metadata["source"] != "test" {
+		t.Errorf("stored metadata = %v", stored.Metadata)
+	}
+	if _, ok := evt.Metadata[MetadataKey]; ok || string(evt.RawBody) != `{"email":"a@b.co","token":"t"}` {
+		t.Error("ApplyEvent modified its input")
+	}
+
+	forwarded := NewBodyPolicy(config.RedactRules{Paths: []string{"token"}}).ApplyEvent(stored)
+	if forwarded.Metadata[MetadataKey] != "2" {
+		t.Errorf("forwarded redactions = %q, want the stored count plus one", forwarded.Metadata[MetadataKey])
+	}
+
+	var nilPolicy *BodyPolicy
+	if got := nilPolicy.ApplyEvent(evt); got.Metadata[MetadataKey] != "" {
+		t.Errorf("nil policy set metadata %v", got.Metadata)
+	}
+}
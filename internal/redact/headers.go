@@ -0,0 +1,101 @@
+// Package redact removes secrets and other sensitive data from events before
+// they are stored or forwarded.
+package redact
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/youmna-rabie/claude-pod/internal/config"
+)
+
+// Redacted replaces the value of a redacted header.
+const Redacted = "[REDACTED]"
+
+// secretHeaders are always redacted.
+var secretHeaders = []string{
+	"Authorization",
+	"Proxy-Authorization",
+	"Cookie",
+	"Set-Cookie",
+}
+
+// secretWords mark a header as a secret when they appear anywhere in its
+// lower-cased name, catching X-Auth-Token, X-Hub-Signature-256 and the like.
+var secretWords = []string{"token", "secret", "password", "signature", "api-key", "apikey", "credential"}
+
+// HeaderPolicy decides which request headers an event keeps.
+type HeaderPolicy struct {
+	allow  []pattern
+	deny   []pattern
+	redact []pattern
+}
+
+// NewHeaderPolicy builds a policy from cfg. The zero config keeps every
+// header and redacts only the built-in secret headers.
+func NewHeaderPolicy(cfg config.HeaderConfig) *HeaderPolicy {
+	return &HeaderPolicy{
+		allow:  compile(cfg.Allow),
+		deny:   compile(cfg.Deny),
+		redact: compile(slices.Concat(secretHeaders, cfg.Redact)),
+	}
+}
+
+// Apply returns the headers p keeps, with secret values replaced by
+// Redacted. Headers not on a non-empty allowlist, or on the denylist, are
+// dropped. headers is not modified.
+func (p *HeaderPolicy) Apply(headers map[string]string) map[string]string {
+	out := make(map[string]string, len(headers))
+	for name, value := range headers {
+		if len(p.allow) > 0 && !matchAny(p.allow, name) {
+			continue
+		}
+		if matchAny(p.deny, name) {
+			continue
+		}
+		if matchAny(p.redact, name) || isSecretName(name) {
+			value = Redacted
+		}
+		out[name] = value
+	}
+	return out
+}
+
+func isSecretName(name string) bool {
+	lower := strings.ToLower(name)
+	for _, word := range secretWords {
+		if strings.Contains(lower, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// pattern is a lower-cased header name, or a prefix if it ended in *.
+type pattern struct {
+	name   string
+	prefix bool
+}
+
+func compile(names []string) []pattern {
+	patterns := make([]pattern, 0, len(names))
+	for _, n := range names {
+		p := pattern{name: n}
+		if strings.HasSuffix(n, "*") {
+			p = pattern{name: strings.TrimSuffix(n, "*"), prefix: true}
+		}
+		p.name = strings.ToLower(p.name)
+		patterns = append(patterns, p)
+	}
+	return patterns
+}
+
+func matchAny(patterns []pattern, name string) bool {
+	lower := strings.ToLower(name)
+	for _, p := range patterns {
+		if p.name == lower || (p.prefix && strings.HasPrefix(lower, p.name)) {
+			return true
+		}
+	}
+	return false
+}
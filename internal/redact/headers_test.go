@@ -0,0 +1,63 @@
+package redact
+
+import (
+	"maps"
+	"testing"
+
+	"github.com/youmna-rabie/claude-pod/internal/config"
+)
+
+func TestHeaderPolicyDefaultsRedactSecrets(t *testing.T) {
+	in := map[string]string{
+		"Authorization":       "Bearer abc",
+		"Cookie":              "session=1",
+		"X-Auth-Token":        "t",
+		"X-Hub-Signature-256": "sha256=ff",
+		"X-Api-Key":           "k",
+		"Content-Type":        "application/json",
+		"User-Agent":          "Grafana",
+	}
+	got := NewHeaderPolicy(config.HeaderConfig{}).Apply(in)
+
+	want := map[string]string{
+		"Authorization":       Redacted,
+		"Cookie":              Redacted,
+		"X-Auth-Token":        Redacted,
+		"X-Hub-Signature-256": Redacted,
+		"X-Api-Key":           Redacted,
+		"Content-Type":        "application/json",
+		"User-Agent":          "Grafana",
+	}
+	if !maps.Equal(got, want) {
+		t.Errorf("Apply() = %v, want %v", got, want)
+	}
+	if in["Authorization"] != "Bearer abc" {
+		t.Error("Apply modified its input")
+	}
+}
+
+func TestHeaderPolicyAllowDenyRedact(t *testing.T) {
+	policy := NewHeaderPolicy(config.HeaderConfig{
+		Allow:  []string{"content-type", "X-Grafana-*", "Authorization"},
+		Deny:   []string{"X-Grafana-Internal"},
+		Redact: []string{"x-grafana-org-id"},
+	})
+	got := policy.Apply(map[string]string{
+		"Content-Type":       "application/json",
+		"X-Grafana-Org-Id":   "42",
+		"X-Grafana-Internal": "yes",
+		"X-Grafana-Instance": "prod",
+		"Authorization":      "Bearer abc",
+		"User-Agent":         "Grafana",
+	})
+
+	want := map[string]string{
+		"Content-Type":       "application/json",
+		"X-Grafana-Org-Id":   Redacted,
+		"X-Grafana-Instance": "prod",
+		"Authorization":      Redacted,
+	}
+	if !maps.Equal(got, want) {
+		t.Errorf("Apply() = %v, want %v", got, want)
+	}
+}
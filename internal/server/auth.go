@@ -0,0 +1,160 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/youmna-rabie/claude-pod/internal/config"
+)
+
+// role is what an admin caller may do. Each role includes the ones below it.
+type role int
+
+const (
+	roleViewer   role = iota + 1 // read events, stats and configuration
+	roleOperator                 // also replay, requeue and discard events
+)
+
+func (r role) String() string {
+	switch r {
+	case roleViewer:
+		return "viewer"
+	case roleOperator:
+		return "operator"
+	default:
+		return "none"
+	}
+}
+
+func parseRole(s string) role {
+	switch s {
+	case "viewer":
+		return roleViewer
+	case "operator":
+		return roleOperator
+	default:
+		return 0
+	}
+}
+
+// adminCaller identifies who made an admin request.
+type adminCaller struct {
+	name string
+	role role
+}
+
+const adminCallerKey ctxKey = "admin_caller"
+
+// anonymousAdmin is the caller when no admin keys are configured.
+var anonymousAdmin = adminCaller{name: "anonymous", role: roleOperator}
+
+func callerFromContext(ctx context.Context) adminCaller {
+	c, _ := ctx.Value(adminCallerKey).(adminCaller)
+	return c
+}
+
+// adminKey is a configured credential. Only its hash is kept, so comparing
+// takes the same time whatever the key's length.
+type adminKey struct {
+	hash   [sha256.Size]byte
+	caller adminCaller
+}
+
+func newAdminKeys(cfgs []config.AdminKeyConfig) []adminKey {
+	keys := make([]adminKey, 0, len(cfgs))
+	for _, k := range cfgs {
+		keys = append(keys, adminKey{
+			hash:   sha256.Sum256([]byte(k.Key)),
+			caller: adminCaller{name: k.Name, role: parseRole(k.Role)},
+		})
+	}
+	return keys
+}
+
+// lookupAdminKey returns the caller token belongs to. Every key is compared
+// so the time taken does not reveal which one matched.
+func lookupAdminKey(keys []adminKey, token string) (adminCaller, bool) {
+	hash := sha256.Sum256([]byte(token))
+	var found adminCaller
+	ok := false
+	for _, k := range keys {
+		if subtle.ConstantTimeCompare(hash[:], k.hash[:]) == 1 {
+			found, ok = k.caller, true
+		}
+	}
+	return found, ok
+}
+
+// adminToken returns the credential sent as "Authorization: Bearer <key>"
+// or in the X-API-Key header.
+func adminToken(r *http.Request) string {
+	if v := r.Header.Get("X-API-Key"); v != "" {
+		return v
+	}
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if ok && strings.EqualFold(scheme, "Bearer") {
+		return strings.TrimSpace(token)
+	}
+	return ""
+}
+
+// authenticateAdmin identifies the caller of an admin request and writes an
+// audit record once it has been handled. With keys configured, a request
+// without a valid one is rejected with 401.
+func (s *Server) authenticateAdmin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+
+		caller := anonymousAdmin
+		if len(s.adminKeys) > 0 {
+			var ok bool
+			caller, ok = lookupAdminKey(s.adminKeys, adminToken(r))
+			if !ok {
+				sw.Header().Set("WWW-Authenticate", `Bearer realm="admin"`)
+				writeJSON(sw, http.StatusUnauthorized, map[string]string{"error": "missing or invalid admin key"})
+				s.auditAdmin(r, adminCaller{}, sw.status)
+				return
+			}
+		}
+
+		next.ServeHTTP(sw, r.WithContext(context.WithValue(r.Context(), adminCallerKey, caller)))
+		s.auditAdmin(r, caller, sw.status)
+	})
+}
+
+// requireRole rejects callers whose role is below min with 403.
+func requireRole(min role) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if caller := callerFromContext(r.Context()); caller.role < min {
+				writeJSON(w, http.StatusForbidden, map[string]string{
+					"error": "requires the " + min.String() + " role",
+				})
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// auditAdmin records one admin request: who made it, what it asked for and
+// how it was answered. An empty caller name means authentication failed.
+func (s *Server) auditAdmin(r *http.Request, caller adminCaller, status int) {
+	attrs := []slog.Attr{
+		slog.String("caller", caller.name),
+		slog.String("role", caller.role.String()),
+		slog.String("method", r.Method),
+		slog.String("path", r.URL.Path),
+		slog.Int("status", status),
+		slog.String("remote_addr", r.RemoteAddr),
+		slog.String("request_id", RequestIDFromContext(r.Context())),
+	}
+	if r.URL.RawQuery != "" {
+		attrs = append(attrs, slog.String("query", r.URL.RawQuery))
+	}
+	s.audit.LogAttrs(r.Context(), slog.LevelInfo, "admin request", attrs...)
+}
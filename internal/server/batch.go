@@ -0,0 +1,116 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/youmna-rabie/claude-pod/internal/agent"
+	"github.com/youmna-rabie/claude-pod/internal/tracing"
+	"github.com/youmna-rabie/claude-pod/internal/types"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// batchResult is the outcome of forwarding one event of a synchronous batch.
+type batchResult struct {
+	EventID  string          `json:"event_id"`
+	Response *agent.Response `json:"response,omitempty"`
+	Error    string          `json:"error,omitempty"`
+}
+
+// handleBatch continues the webhook pipeline for the events of one batch
+// request. Each is filtered, redacted and stored like a single event. In
+// async mode they are queued and the sender gets 202 with their IDs. In sync
+// mode they are forwarded one after another and the sender gets each
+// result, with 502 if any forward failed, or 503 if every failure was the
+// circuit breaker refusing the forward.
+//
+// If an event cannot be stored, or the async queue cannot take one, those
+// already stored are marked failed so the batch can be resent in full.
+func (s *Server) handleBatch(w http.ResponseWriter, r *http.Request, channelName string, events []*types.Event) {
+	trace.SpanFromContext(r.Context()).SetAttributes(attribute.Int("gateway.batch_size", len(events)))
+
+	// Store
+	_, stage := tracing.Tracer().Start(r.Context(), "store")
+	stored := make([]types.Event, 0, len(events))
+	for _, evt := range events {
+		evt.Headers = s.headers[channelName].Apply(evt.Headers)
+		e := s.bodies[channelName].store.ApplyEvent(*evt)
+		if err := s.store.Save(e); err != nil {
+			endStage(stage, err)
+			s.logger.Error("failed to save event", "error", err, "event_id", e.ID)
+			for _, saved := range stored {
+				s.fail(saved, "batch not stored in full")
+			}
+			writeJSON(w, http.StatusInternalServerError, map[string]string{
+				"error": "failed to store event",
+			})
+			return
+		}
+		s.stats.received(channelName, time.Now())
+		stored = append(stored, e)
+	}
+	endStage(stage, nil)
+
+	if s.modes[channelName] == modeAsync {
+		accepted := make([]string, 0, len(stored))
+		for i, evt := range stored {
+			if err := s.enqueue(r, evt); err != nil {
+				for _, rest := range stored[i+1:] {
+					s.fail(rest, "batch rejected: "+err.Error())
+				}
+				status, wait := queueErrorStatus(err)
+				w.Header().Set("Retry-After", wait)
+				writeJSON(w, status, map[string]any{"error": err.Error(), "accepted": accepted})
+				return
+			}
+			accepted = append(accepted, evt.ID.String())
+		}
+		writeJSON(w, http.StatusAccepted, map[string]any{"status": "accepted", "event_ids": accepted})
+		return
+	}
+
+	// Forward
+	if !s.beginForward() {
+		for _, evt := range stored {
+			s.fail(evt, "gateway is shutting down")
+		}
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{
+			"error": "gateway is shutting down",
+		})
+		return
+	}
+	defer s.inflight.Done()
+
+	var failed, rejected int // forwards that failed, and those refused by the open breaker
+	results := make([]batchResult, 0, len(stored))
+	for _, evt := range stored {
+		ctx, cancel := s.forwardContext(r.Context())
+		resp, err := s.forward(ctx, evt, false)
+		cancel()
+
+		res := batchResult{EventID: evt.ID.String()}
+		switch {
+		case errors.Is(err, agent.ErrCircuitOpen):
+			res.Error = "agent unavailable"
+			failed++
+			rejected++
+		case err != nil:
+			res.Error = "agent forwarding failed"
+			failed++
+		default:
+			res.Response = &resp
+		}
+		results = append(results, res)
+	}
+
+	status := http.StatusOK
+	switch {
+	case failed > 0 && rejected == failed:
+		status = http.StatusServiceUnavailable
+	case failed > 0:
+		status = http.StatusBadGateway
+	}
+	writeJSON(w, status, map[string]any{"results": results})
+}
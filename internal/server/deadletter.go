@@ -0,0 +1,162 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/youmna-rabie/claude-pod/internal/event"
+	"github.com/youmna-rabie/claude-pod/internal/types"
+)
+
+// deadLetter copies an event that failed every forward attempt into the
+// dead-letter queue, or records the new failure if it is already there.
+func (s *Server) deadLetter(evt types.Event, change types.StatusChange) {
+	if err := s.dead.Transition(evt.ID, change); err == nil {
+		return
+	}
+
+	evt.Status = types.EventStatusDeadLettered
+	if change.Attempt > 0 {
+		evt.Attempts = change.Attempt
+	}
+	if err := s.dead.Save(evt); err != nil {
+		s.logger.Error("failed to dead-letter event", "error", err, "event_id", evt.ID)
+		return
+	}
+	s.logger.Warn("event moved to dead-letter queue", "event_id", evt.ID, "channel", evt.ChannelID)
+}
+
+// deadLetterEvent resolves the {id} route parameter to a dead-lettered event,
+// writing an error response and returning false if that fails.
+func (s *Server) deadLetterEvent(w http.ResponseWriter, r *http.Request) (types.Event, bool) {
+	if s.dead == nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "dead-letter queue is not enabled"})
+		return types.Event{}, false
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid event id"})
+		return types.Event{}, false
+	}
+	evt, err := s.dead.Get(id)
+	if errors.Is(err, event.ErrNotFound) {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "event not in dead-letter queue"})
+		return types.Event{}, false
+	}
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get dead letter"})
+		return types.Event{}, false
+	}
+	return evt, true
+}
+
+// handleDeadLetters responds to GET /admin/dead-letters with dead-lettered
+// events, filtered and paged like GET /admin/events.
+func (s *Server) handleDeadLetters(w http.ResponseWriter, r *http.Request) {
+	if s.dead == nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "dead-letter queue is not enabled"})
+		return
+	}
+	q, err := parseEventQuery(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	page, err := s.dead.Query(q)
+	if errors.Is(err, event.ErrInvalidCursor) {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{
+			"error": "failed to list dead letters",
+		})
+		return
+	}
+
+	resp := map[string]any{
+		"events": page.Events,
+		"count":  len(page.Events),
+		"total":  s.dead.Count(),
+	}
+	if page.NextCursor != "" {
+		resp["next_cursor"] = page.NextCursor
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// handleDeadLetter responds to GET /admin/dead-letters/{id} with the event
+// and its history. The event store keeps the full timeline; once the event
+// has been evicted there, the dead-letter queue's own history is returned.
+func (s *Server) handleDeadLetter(w http.ResponseWriter, r *http.Request) {
+	evt, ok := s.deadLetterEvent(w, r)
+	if !ok {
+		return
+	}
+
+	history, err := s.store.History(evt.ID)
+	if err != nil {
+		history, _ = s.dead.History(evt.ID)
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"event":   evt,
+		"history": history,
+	})
+}
+
+// handleRequeueDeadLetter responds to POST /admin/dead-letters/{id}/requeue
+// by replaying the event. On success it leaves the dead-letter queue; on
+// failure it stays there with the new attempt recorded.
+func (s *Server) handleRequeueDeadLetter(w http.ResponseWriter, r *http.Request) {
+	evt, ok := s.deadLetterEvent(w, r)
+	if !ok {
+		return
+	}
+
+	// Restore the event if it has since been evicted from the event store,
+	// so the outcome is recorded there too.
+	if _, err := s.store.Get(evt.ID); errors.Is(err, event.ErrNotFound) {
+		if err := s.store.Save(evt); err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to restore event"})
+			return
+		}
+	}
+
+	if !s.beginForward() {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{
+			"error": "gateway is shutting down",
+		})
+		return
+	}
+	defer s.inflight.Done()
+
+	result, err := s.replay(r.Context(), evt)
+	writeReplayResult(w, result, err)
+}
+
+// handleDiscardDeadLetter responds to DELETE /admin/dead-letters/{id} by
+// dropping the event from the dead-letter queue and marking it failed.
+func (s *Server) handleDiscardDeadLetter(w http.ResponseWriter, r *http.Request) {
+	evt, ok := s.deadLetterEvent(w, r)
+	if !ok {
+		return
+	}
+
+	if err := s.dead.Delete(evt.ID); err != nil && !errors.Is(err, event.ErrNotFound) {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to discard dead letter"})
+		return
+	}
+	// An operator decision, not a channel failure, so it is not counted in stats.
+	_ = s.store.Transition(evt.ID, types.StatusChange{
+		Status: types.EventStatusFailed,
+		Error:  "discarded from dead-letter queue",
+	})
+
+	writeJSON(w, http.StatusOK, map[string]string{
+		"status":   "discarded",
+		"event_id": evt.ID.String(),
+	})
+}
@@ -6,7 +6,9 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
+	"github.com/youmna-rabie/claude-pod/internal/metrics"
 )
 
 type ctxKey string
@@ -36,12 +38,15 @@ func RequestID(next http.Handler) http.Handler {
 }
 
 // Logging returns middleware that logs each request's method, path, status, and duration.
-func Logging(logger *slog.Logger) func(http.Handler) http.Handler {
+// It also records them in m, which may be nil, labelled by route pattern.
+func Logging(logger *slog.Logger, m *metrics.Metrics) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			start := time.Now()
 			sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+			m.RequestStarted()
 			next.ServeHTTP(sw, r)
+			m.RequestFinished(r.Method, routePattern(r), sw.status, time.Since(start))
 			logger.Info("request",
 				"method", r.Method,
 				"path", r.URL.Path,
@@ -53,6 +58,17 @@ func Logging(logger *slog.Logger) func(http.Handler) http.Handler {
 	}
 }
 
+// routePattern returns the chi route pattern that matched r, such as
+// /webhooks/{channel}, or "unmatched" for requests no route handled.
+func routePattern(r *http.Request) string {
+	if rctx := chi.RouteContext(r.Context()); rctx != nil {
+		if p := rctx.RoutePattern(); p != "" {
+			return p
+		}
+	}
+	return "unmatched"
+}
+
 // Recovery returns middleware that recovers from panics in downstream handlers,
 // logs the error, and returns a 500 response.
 func Recovery(logger *slog.Logger) func(http.Handler) http.Handler {
@@ -85,3 +101,9 @@ func (sw *statusWriter) WriteHeader(code int) {
 	sw.status = code
 	sw.ResponseWriter.WriteHeader(code)
 }
+
+// Unwrap returns the underlying ResponseWriter, so http.ResponseController
+// can reach its Flush for streaming responses.
+func (sw *statusWriter) Unwrap() http.ResponseWriter {
+	return sw.ResponseWriter
+}
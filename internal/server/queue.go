@@ -0,0 +1,140 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/youmna-rabie/claude-pod/internal/types"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	modeSync  = "sync"
+	modeAsync = "async"
+)
+
+// job is an async event waiting to be forwarded, with the ID and trace
+// context of the request that delivered it.
+type job struct {
+	evt       types.Event
+	requestID string
+	trace     trace.SpanContext
+}
+
+// startWorkers creates the async queue and launches n workers draining it.
+func (s *Server) startWorkers(n, size int) {
+	s.queue = make(chan job, max(size, 1))
+	for i := 0; i < max(n, 1); i++ {
+		s.workers.Add(1)
+		go s.work()
+	}
+}
+
+// work forwards queued events until the queue is closed.
+func (s *Server) work() {
+	defer s.workers.Done()
+	for j := range s.queue {
+		ctx := context.WithValue(context.Background(), requestIDKey, j.requestID)
+		ctx = trace.ContextWithSpanContext(ctx, j.trace)
+		ctx, cancel := s.forwardContext(ctx)
+		_, _ = s.forward(ctx, j.evt, false)
+		cancel()
+	}
+}
+
+// Errors returned by enqueue.
+var (
+	errShuttingDown = errors.New("gateway is shutting down")
+	errQueueFull    = errors.New("queue full")
+)
+
+// accept queues a stored event for asynchronous forwarding and responds with
+// 202 and the event ID. When the queue is full the event is marked failed and
+// the sender is told to back off with 429; during shutdown it gets 503.
+func (s *Server) accept(w http.ResponseWriter, r *http.Request, evt types.Event) {
+	if err := s.enqueue(r, evt); err != nil {
+		status, wait := queueErrorStatus(err)
+		w.Header().Set("Retry-After", wait)
+		writeJSON(w, status, map[string]string{"error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusAccepted, map[string]string{
+		"status":   "accepted",
+		"event_id": evt.ID.String(),
+	})
+}
+
+// enqueue queues a stored event for a worker. If the queue is full or closed
+// the event is marked failed and errQueueFull or errShuttingDown returned.
+func (s *Server) enqueue(r *http.Request, evt types.Event) error {
+	s.queueMu.RLock()
+	defer s.queueMu.RUnlock()
+
+	if s.closed {
+		s.fail(evt, errShuttingDown.Error())
+		return errShuttingDown
+	}
+
+	select {
+	case s.queue <- job{evt: evt, requestID: RequestIDFromContext(r.Context()), trace: trace.SpanContextFromContext(r.Context())}:
+		return nil
+	default:
+		s.fail(evt, "async queue full")
+		s.logger.Warn("async queue full, rejecting event", "event_id", evt.ID, "channel", evt.ChannelID)
+		return errQueueFull
+	}
+}
+
+// queueErrorStatus returns the response status and Retry-After value for
+// an error from enqueue.
+func queueErrorStatus(err error) (int, string) {
+	if errors.Is(err, errShuttingDown) {
+		return http.StatusServiceUnavailable, "5"
+	}
+	return http.StatusTooManyRequests, "1"
+}
+
+// beginForward registers an in-flight synchronous forward so Shutdown can
+// wait for it. It returns false once shutdown has started.
+func (s *Server) beginForward() bool {
+	s.queueMu.RLock()
+	defer s.queueMu.RUnlock()
+	if s.closed {
+		return false
+	}
+	s.inflight.Add(1)
+	return true
+}
+
+// Shutdown ends open event streams, stops accepting async events and waits
+// for queued and in-flight forwards to finish. If ctx is done first, outstanding forwards are
+// cancelled, which marks their events failed, and ctx's error is returned
+// once they have returned.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.CloseStreams()
+
+	s.queueMu.Lock()
+	if !s.closed && s.queue != nil {
+		close(s.queue)
+	}
+	s.closed = true
+	s.queueMu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.workers.Wait()
+		s.inflight.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		s.logger.Warn("shutdown deadline reached, cancelling in-flight forwards")
+		s.cancel()
+		<-done
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,116 @@
+package server
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/youmna-rabie/claude-pod/internal/config"
+	"golang.org/x/time/rate"
+)
+
+// idleLimiterTTL is the shortest time a per-IP bucket is kept after its last
+// request. Slow buckets are kept until they have had time to refill, so
+// dropping one never hands its IP a full burst early.
+const idleLimiterTTL = 10 * time.Minute
+
+// rateLimiter is a channel's token bucket, or one bucket per source IP.
+type rateLimiter struct {
+	limit   rate.Limit
+	burst   int
+	perIP   bool
+	idleTTL time.Duration // how long an idle per-IP bucket is kept
+
+	mu        sync.Mutex
+	shared    *rate.Limiter
+	byIP      map[string]*ipLimiter
+	lastSweep time.Time
+}
+
+type ipLimiter struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// newRateLimiter returns a limiter for cfg, or nil if cfg disables limiting.
+func newRateLimiter(cfg config.RateLimitConfig) *rateLimiter {
+	if cfg.Rate <= 0 {
+		return nil
+	}
+	l := &rateLimiter{
+		limit: rate.Limit(cfg.Rate),
+		burst: cfg.Burst,
+		perIP: cfg.PerIP,
+	}
+	if l.perIP {
+		// Keep idle buckets at least as long as an empty one takes to
+		// refill, capped well short of overflowing a Duration.
+		refill := min(float64(l.burst)/cfg.Rate, 1e9) // seconds
+		l.idleTTL = max(idleLimiterTTL, time.Duration(refill*float64(time.Second)))
+		l.byIP = make(map[string]*ipLimiter)
+	} else {
+		l.shared = rate.NewLimiter(l.limit, l.burst)
+	}
+	return l
+}
+
+// allow takes a token for r. If none is available it returns false and how
+// long until one will be.
+func (l *rateLimiter) allow(r *http.Request) (bool, time.Duration) {
+	now := time.Now()
+	res := l.limiter(r, now).ReserveN(now, 1)
+	if !res.OK() {
+		return false, time.Second
+	}
+	if delay := res.DelayFrom(now); delay > 0 {
+		res.CancelAt(now)
+		return false, delay
+	}
+	return true, 0
+}
+
+// limiter returns the bucket r draws from.
+func (l *rateLimiter) limiter(r *http.Request, now time.Time) *rate.Limiter {
+	if !l.perIP {
+		return l.shared
+	}
+
+	ip := clientIP(r)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Sub(l.lastSweep) > l.idleTTL {
+		for key, il := range l.byIP {
+			if now.Sub(il.lastSeen) > l.idleTTL {
+				delete(l.byIP, key)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	il, ok := l.byIP[ip]
+	if !ok {
+		il = &ipLimiter{limiter: rate.NewLimiter(l.limit, l.burst)}
+		l.byIP[ip] = il
+	}
+	il.lastSeen = now
+	return il.limiter
+}
+
+// clientIP returns the host part of r.RemoteAddr. Forwarding headers are
+// not trusted, so behind a proxy every request shares the proxy's bucket.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// retryAfter formats a delay as a Retry-After value in whole seconds, at least 1.
+func retryAfter(d time.Duration) string {
+	return strconv.Itoa(max(1, int(math.Ceil(d.Seconds()))))
+}
@@ -0,0 +1,227 @@
+package server
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/youmna-rabie/claude-pod/internal/agent"
+	"github.com/youmna-rabie/claude-pod/internal/event"
+	"github.com/youmna-rabie/claude-pod/internal/types"
+)
+
+// replayResult reports the outcome of replaying one event.
+type replayResult struct {
+	EventID  uuid.UUID         `json:"event_id"`
+	Status   types.EventStatus `json:"status"`
+	Attempts int               `json:"attempts,omitempty"`
+	Error    string            `json:"error,omitempty"`
+	Response json.RawMessage   `json:"response,omitempty"`
+}
+
+// replay re-sends a stored event to the agent with the current skills.
+// The outcome is recorded in the event's history as a replay.
+func (s *Server) replay(parent context.Context, evt types.Event) (replayResult, error) {
+	ctx, cancel := s.forwardContext(parent)
+	defer cancel()
+
+	resp, err := s.forward(ctx, evt, true)
+	result := replayResult{
+		EventID:  evt.ID,
+		Status:   types.EventStatusForwarded,
+		Attempts: agent.Attempts(resp, err),
+		Response: resp.Body,
+	}
+	if err != nil {
+		result.Status = s.failureStatus(err)
+		result.Error = err.Error()
+	}
+	return result, err
+}
+
+// handleReplayEvent responds to POST /admin/events/{id}/replay by forwarding
+// the stored event again and returning the outcome.
+func (s *Server) handleReplayEvent(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid event id"})
+		return
+	}
+
+	evt, err := s.store.Get(id)
+	if errors.Is(err, event.ErrNotFound) {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "event not found"})
+		return
+	}
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get event"})
+		return
+	}
+
+	if !s.beginForward() {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{
+			"error": "gateway is shutting down",
+		})
+		return
+	}
+	defer s.inflight.Done()
+
+	result, err := s.replay(r.Context(), evt)
+	writeReplayResult(w, result, err)
+}
+
+// writeReplayResult responds with a single replay's outcome, using the same
+// status codes as a synchronous webhook forward.
+func writeReplayResult(w http.ResponseWriter, result replayResult, err error) {
+	switch {
+	case errors.Is(err, agent.ErrCircuitOpen):
+		writeJSON(w, http.StatusServiceUnavailable, result)
+	case err != nil:
+		writeJSON(w, http.StatusBadGateway, result)
+	default:
+		writeJSON(w, http.StatusOK, result)
+	}
+}
+
+// handleReplay responds to POST /admin/replay by replaying a batch of events
+// matching the same query parameters as GET /admin/events. At least one
+// filter is required so an empty request cannot resend the whole store.
+// Every match is collected first so that the batch holds the oldest limit
+// events, which are replayed one at a time; next_cursor continues with the
+// following batch.
+func (s *Server) handleReplay(w http.ResponseWriter, r *http.Request) {
+	q, err := parseEventQuery(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	if !isFiltered(q) {
+		writeJSON(w, http.StatusBadRequest, map[string]string{
+			"error": "at least one filter is required",
+		})
+		return
+	}
+	after, err := decodeReplayCursor(q.Cursor)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	events, err := s.replayCandidates(q, after)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{
+			"error": "failed to list events",
+		})
+		return
+	}
+	limit := q.Limit
+	if limit <= 0 {
+		limit = event.DefaultQueryLimit
+	}
+	batch := events[:min(limit, event.MaxQueryLimit, len(events))]
+
+	if !s.beginForward() {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{
+			"error": "gateway is shutting down",
+		})
+		return
+	}
+	defer s.inflight.Done()
+
+	results := make([]replayResult, 0, len(batch))
+	failed := 0
+	for _, evt := range batch {
+		if r.Context().Err() != nil {
+			break
+		}
+		result, err := s.replay(r.Context(), evt)
+		if err != nil {
+			failed++
+		}
+		results = append(results, result)
+	}
+
+	resp := map[string]any{
+		"results":   results,
+		"count":     len(results),
+		"succeeded": len(results) - failed,
+		"failed":    failed,
+	}
+	if len(results) > 0 && len(results) < len(events) {
+		resp["next_cursor"] = encodeReplayCursor(batch[len(results)-1])
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// replayCandidates returns every stored event matching q's filters that comes
+// after the replay cursor position after, oldest first. Events are ordered by
+// timestamp, then ID.
+func (s *Server) replayCandidates(q event.Query, after *types.Event) ([]types.Event, error) {
+	q.Cursor, q.Limit = "", event.MaxQueryLimit
+	var events []types.Event
+	for {
+		page, err := s.store.Query(q)
+		if err != nil {
+			return nil, err
+		}
+		for _, evt := range page.Events {
+			if after == nil || compareReplayOrder(evt, *after) > 0 {
+				events = append(events, evt)
+			}
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		q.Cursor = page.NextCursor
+	}
+	slices.SortFunc(events, compareReplayOrder)
+	return events, nil
+}
+
+// compareReplayOrder orders events oldest first, breaking ties by ID.
+func compareReplayOrder(a, b types.Event) int {
+	if c := cmp.Compare(a.Timestamp.UnixNano(), b.Timestamp.UnixNano()); c != 0 {
+		return c
+	}
+	return bytes.Compare(a.ID[:], b.ID[:])
+}
+
+// Replay cursors hold the timestamp and ID of the last event replayed. They
+// stay valid while replays change the status of the events they pass over.
+
+func encodeReplayCursor(evt types.Event) string {
+	return strconv.FormatInt(evt.Timestamp.UnixNano(), 36) + "." + evt.ID.String()
+}
+
+// decodeReplayCursor returns the position to continue after, as an event
+// with only Timestamp and ID set, or nil for no cursor.
+func decodeReplayCursor(cursor string) (*types.Event, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+	ts, id, ok := strings.Cut(cursor, ".")
+	nanos, err := strconv.ParseInt(ts, 36, 64)
+	if !ok || err != nil {
+		return nil, event.ErrInvalidCursor
+	}
+	evt := types.Event{Timestamp: time.Unix(0, nanos)}
+	if evt.ID, err = uuid.Parse(id); err != nil {
+		return nil, event.ErrInvalidCursor
+	}
+	return &evt, nil
+}
+
+// isFiltered reports whether q narrows the events it matches.
+func isFiltered(q event.Query) bool {
+	return q.ChannelID != "" || q.Status != "" || !q.Since.IsZero() || !q.Until.IsZero() ||
+		q.HeaderContains != "" || q.BodyContains != ""
+}
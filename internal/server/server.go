@@ -3,17 +3,29 @@ package server
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
+	"maps"
 	"net"
 	"net/http"
+	"slices"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
 	"github.com/youmna-rabie/claude-pod/internal/agent"
 	"github.com/youmna-rabie/claude-pod/internal/config"
 	"github.com/youmna-rabie/claude-pod/internal/event"
+	"github.com/youmna-rabie/claude-pod/internal/metrics"
+	"github.com/youmna-rabie/claude-pod/internal/redact"
+	"github.com/youmna-rabie/claude-pod/internal/tracing"
 	"github.com/youmna-rabie/claude-pod/internal/types"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Server is the HTTP gateway that receives webhooks, stores events,
@@ -21,14 +33,82 @@ import (
 type Server struct {
 	cfg      *config.Config
 	store    event.Store
+	events   *event.PublishingStore // store, publishing to event streams
 	channels map[string]types.Channel
 	agent    agent.Client
 	skills   []types.Skill
+	breaker  *agent.Breaker
+	dead     event.Store      // dead-letter queue; nil when disabled
+	metrics  *metrics.Metrics // nil when disabled
+	stats    *stats
+	modes    map[string]string               // channel name → delivery mode
+	limiters map[string]*rateLimiter         // channel name → rate limit; absent when unlimited
+	headers  map[string]*redact.HeaderPolicy // channel name → headers kept on its events
+	bodies   map[string]bodyRedaction        // channel name → body redaction; absent when none
 	router   chi.Router
+	admin    chi.Router // admin API on its own listener; nil when served by router
 	logger   *slog.Logger
+
+	adminKeys []adminKey   // empty leaves the admin API open
+	audit     *slog.Logger // records every admin request
+
+	queue    chan job     // async events awaiting a worker
+	queueMu  sync.RWMutex // guards closing queue against concurrent sends
+	closed   bool
+	workers  sync.WaitGroup
+	inflight sync.WaitGroup // synchronous forwards in progress
+
+	// baseCtx parents every forward and is cancelled when Shutdown runs out of time.
+	baseCtx context.Context
+	cancel  context.CancelFunc
+
+	streamsDone chan struct{} // closed by CloseStreams
+	streamsOnce sync.Once
+}
+
+// bodyRedaction holds a channel's body redaction policies.
+type bodyRedaction struct {
+	store   *redact.BodyPolicy // applied before storing
+	forward *redact.BodyPolicy // applied on top before forwarding
+}
+
+// Option configures optional Server dependencies.
+type Option func(*Server)
+
+// WithBreaker exposes the agent circuit breaker on GET /admin/breaker.
+func WithBreaker(b *agent.Breaker) Option {
+	return func(s *Server) {
+		s.breaker = b
+	}
+}
+
+// WithDeadLetters moves events that fail every forward attempt into store
+// and exposes it under /admin/dead-letters.
+func WithDeadLetters(store event.Store) Option {
+	return func(s *Server) {
+		s.dead = store
+	}
+}
+
+// WithMetrics records request, webhook and forward metrics in m and serves
+// them on GET /metrics.
+func WithMetrics(m *metrics.Metrics) Option {
+	return func(s *Server) {
+		s.metrics = m
+	}
+}
+
+// WithAuditLog writes the admin audit trail to logger instead of the
+// server's logger.
+func WithAuditLog(logger *slog.Logger) Option {
+	return func(s *Server) {
+		s.audit = logger
+	}
 }
 
-// NewServer creates a Server wired with the given dependencies.
+// NewServer creates a Server wired with the given dependencies. The admin
+// API is served by the Server itself unless cfg.Admin.Port is set, in which
+// case it is only reachable through AdminHandler.
 func NewServer(
 	cfg *config.Config,
 	store event.Store,
@@ -36,36 +116,129 @@ func NewServer(
 	agentClient agent.Client,
 	skills []types.Skill,
 	logger *slog.Logger,
+	opts ...Option,
 ) *Server {
+	events, ok := store.(*event.PublishingStore)
+	if !ok {
+		events = event.NewPublishingStore(store)
+	}
 	s := &Server{
-		cfg:      cfg,
-		store:    store,
-		channels: channels,
-		agent:    agentClient,
-		skills:   skills,
-		logger:   logger,
+		cfg:         cfg,
+		store:       events,
+		events:      events,
+		channels:    channels,
+		agent:       agentClient,
+		skills:      skills,
+		logger:      logger,
+		adminKeys:   newAdminKeys(cfg.Admin.Keys),
+		audit:       logger,
+		streamsDone: make(chan struct{}),
+	}
+	s.baseCtx, s.cancel = context.WithCancel(context.Background())
+	s.stats = newStats(slices.Collect(maps.Keys(channels)))
+	for _, opt := range opts {
+		opt(s)
 	}
 
-	r := chi.NewRouter()
-	r.Use(RequestID)
-	r.Use(Logging(logger))
-	r.Use(Recovery(logger))
+	s.modes = make(map[string]string, len(cfg.Channels))
+	s.limiters = make(map[string]*rateLimiter)
+	s.headers = make(map[string]*redact.HeaderPolicy, len(channels))
+	s.bodies = make(map[string]bodyRedaction)
+	for _, ch := range cfg.Channels {
+		s.modes[ch.Name] = ch.Mode
+		if l := newRateLimiter(ch.RateLimit); l != nil {
+			s.limiters[ch.Name] = l
+		}
+		headers := ch.Headers
+		if ch.Type == "hmac" {
+			// The signature header can have any name, so it may not look
+			// like a secret to the policy.
+			headers.Redact = append(slices.Clone(headers.Redact), ch.HMAC.Header)
+		}
+		s.headers[ch.Name] = redact.NewHeaderPolicy(headers)
+		b := bodyRedaction{
+			store:   redact.NewBodyPolicy(ch.Redact.Store),
+			forward: redact.NewBodyPolicy(ch.Redact.Forward),
+		}
+		if b.store != nil || b.forward != nil {
+			s.bodies[ch.Name] = b
+		}
+		if ch.Mode == modeAsync && s.queue == nil {
+			s.startWorkers(cfg.Server.Workers, cfg.Server.QueueSize)
+		}
+	}
+
+	for name := range channels {
+		if _, ok := s.headers[name]; !ok {
+			s.headers[name] = redact.NewHeaderPolicy(config.HeaderConfig{})
+		}
+	}
 
+	r := s.newRouter()
 	r.Post("/webhooks/{channel}", s.handleWebhook)
-	r.Get("/health", s.handleHealth)
-	r.Get("/admin/events", s.handleAdminEvents)
-	r.Get("/admin/channels", s.handleAdminChannels)
-	r.Get("/admin/skills", s.handleAdminSkills)
+	if s.metrics != nil {
+		r.Method(http.MethodGet, "/metrics", s.metrics.Handler())
+	}
+	if cfg.Admin.Port != 0 {
+		s.admin = s.newRouter()
+		s.admin.Route("/admin", s.adminRoutes)
+	} else {
+		r.Route("/admin", s.adminRoutes)
+	}
 
 	s.router = r
 	return s
 }
 
+// newRouter returns a router with the common middleware and health check.
+func (s *Server) newRouter() chi.Router {
+	r := chi.NewRouter()
+	r.Use(RequestID)
+	r.Use(Logging(s.logger, s.metrics))
+	r.Use(Recovery(s.logger))
+	r.Get("/health", s.handleHealth)
+	return r
+}
+
+// adminRoutes registers the admin API. Every route needs an admin key when
+// any are configured; those that change events need the operator role.
+func (s *Server) adminRoutes(r chi.Router) {
+	r.Use(s.authenticateAdmin)
+	r.Use(requireRole(roleViewer))
+
+	r.Get("/events", s.handleAdminEvents)
+	r.Get("/events/stream", s.handleEventStream)
+	r.Get("/events/{id}", s.handleAdminEvent)
+	r.Get("/dead-letters", s.handleDeadLetters)
+	r.Get("/dead-letters/{id}", s.handleDeadLetter)
+	r.Get("/stats", s.handleAdminStats)
+	r.Get("/channels", s.handleAdminChannels)
+	r.Get("/skills", s.handleAdminSkills)
+	r.Get("/breaker", s.handleAdminBreaker)
+
+	r.Group(func(r chi.Router) {
+		r.Use(requireRole(roleOperator))
+		r.Post("/events/{id}/replay", s.handleReplayEvent)
+		r.Post("/replay", s.handleReplay)
+		r.Post("/dead-letters/{id}/requeue", s.handleRequeueDeadLetter)
+		r.Delete("/dead-letters/{id}", s.handleDiscardDeadLetter)
+	})
+}
+
 // ServeHTTP implements http.Handler.
 func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	s.router.ServeHTTP(w, r)
 }
 
+// AdminHandler returns the handler for the separate admin listener, or nil
+// if the admin API is served by the Server itself.
+func (s *Server) AdminHandler() http.Handler {
+	if s.admin == nil {
+		return nil
+	}
+	return s.admin
+}
+
 // ListenAndServe starts the HTTP server on the configured host:port.
 func (s *Server) ListenAndServe() error {
 	addr := net.JoinHostPort(s.cfg.Server.Host, fmt.Sprintf("%d", s.cfg.Server.Port))
@@ -79,20 +252,66 @@ func (s *Server) ListenAndServe() error {
 }
 
 // handleWebhook processes POST /webhooks/{channel}.
-// Pipeline: validate → parse → store → forward → respond.
+// Pipeline: rate limit → validate → parse → filter headers and redact →
+// store → forward → respond.
+// Channels in async mode are queued after storing and answered with 202.
+// A channel may answer a request itself by returning a *types.Reply from
+// ParseRequest, in which case nothing is stored or forwarded. Batch
+// requests to a types.BatchChannel continue in handleBatch after parsing.
+// A types.DedupChannel is told to forget an event the gateway failed to
+// store or forward.
+// Each stage is traced as a child of a span continuing the sender's
+// traceparent, if it sent one.
 func (s *Server) handleWebhook(w http.ResponseWriter, r *http.Request) {
 	channelName := chi.URLParam(r, "channel")
 
+	ctx, span := tracing.Tracer().Start(tracing.Extract(r.Context(), r.Header), "POST /webhooks/{channel}",
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(attribute.String("gateway.channel", channelName)),
+	)
+	defer span.End()
+	r = r.WithContext(ctx)
+
 	ch, ok := s.channels[channelName]
 	if !ok {
+		span.SetStatus(codes.Error, "unknown channel")
 		writeJSON(w, http.StatusNotFound, map[string]string{
 			"error": fmt.Sprintf("unknown channel: %s", channelName),
 		})
 		return
 	}
 
+	sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+	w = sw
+	defer func() {
+		s.metrics.Webhook(channelName, sw.status)
+		span.SetAttributes(attribute.Int("http.response.status_code", sw.status))
+		if sw.status >= 500 {
+			span.SetStatus(codes.Error, http.StatusText(sw.status))
+		}
+	}()
+
+	// Rate limit
+	if l := s.limiters[channelName]; l != nil {
+		if ok, wait := l.allow(r); !ok {
+			s.metrics.WebhookRejected(channelName, "rate_limit")
+			s.stats.rateLimited(channelName)
+			span.AddEvent("rate limited")
+			w.Header().Set("Retry-After", retryAfter(wait))
+			writeJSON(w, http.StatusTooManyRequests, map[string]string{
+				"error": "rate limit exceeded",
+			})
+			return
+		}
+	}
+
 	// Validate
-	if err := ch.ValidateRequest(r); err != nil {
+	_, stage := tracing.Tracer().Start(ctx, "validate")
+	err := ch.ValidateRequest(r)
+	endStage(stage, err)
+	if err != nil {
+		s.metrics.WebhookRejected(channelName, "validation")
+		s.stats.rejected(channelName, "validation", err)
 		writeJSON(w, http.StatusBadRequest, map[string]string{
 			"error": err.Error(),
 		})
@@ -100,67 +319,335 @@ func (s *Server) handleWebhook(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Parse
-	evt, err := ch.ParseRequest(r)
+	_, stage = tracing.Tracer().Start(ctx, "parse")
+	var (
+		evt   *types.Event
+		batch []*types.Event
+	)
+	bc, isBatch := ch.(types.BatchChannel)
+	isBatch = isBatch && bc.IsBatch(r)
+	if isBatch {
+		batch, err = bc.ParseBatch(r)
+	} else {
+		evt, err = ch.ParseRequest(r)
+	}
+	var reply *types.Reply
+	if errors.As(err, &reply) {
+		endStage(stage, nil)
+		span.AddEvent("answered by channel")
+		writeJSON(w, reply.Status, reply.Body)
+		return
+	}
+	endStage(stage, err)
 	if err != nil {
+		s.metrics.WebhookRejected(channelName, "parse")
+		s.stats.rejected(channelName, "parse", err)
 		writeJSON(w, http.StatusBadRequest, map[string]string{
 			"error": err.Error(),
 		})
 		return
 	}
+	if isBatch {
+		s.handleBatch(w, r, channelName, batch)
+		return
+	}
+	span.SetAttributes(attribute.String("gateway.event_id", evt.ID.String()))
+	if dc, ok := ch.(types.DedupChannel); ok {
+		defer func() {
+			if sw.status >= http.StatusBadRequest {
+				dc.Forget(evt)
+			}
+		}()
+	}
+
+	// Filter headers for every channel here, so no adapter can leak secrets.
+	evt.Headers = s.headers[channelName].Apply(evt.Headers)
+	*evt = s.bodies[channelName].store.ApplyEvent(*evt)
 
 	// Store
-	if err := s.store.Save(*evt); err != nil {
+	_, stage = tracing.Tracer().Start(ctx, "store")
+	err = s.store.Save(*evt)
+	endStage(stage, err)
+	if err != nil {
 		s.logger.Error("failed to save event", "error", err, "event_id", evt.ID)
 		writeJSON(w, http.StatusInternalServerError, map[string]string{
 			"error": "failed to store event",
 		})
 		return
 	}
+	s.stats.received(channelName, time.Now())
+
+	if s.modes[channelName] == modeAsync {
+		s.accept(w, r, *evt)
+		return
+	}
 
 	// Forward
-	envelope := types.EventEnvelope{
-		Version:   "1",
-		Event:     *evt,
-		Channel:   channelName,
-		Skills:    s.skills,
-		Timestamp: time.Now(),
+	if !s.beginForward() {
+		s.fail(*evt, "gateway is shutting down")
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{
+			"error": "gateway is shutting down",
+		})
+		return
 	}
+	defer s.inflight.Done()
+
+	ctx, cancel := s.forwardContext(r.Context())
+	defer cancel()
 
-	resp, err := s.agent.Forward(context.Background(), envelope)
+	resp, err := s.forward(ctx, *evt, false)
+	if errors.Is(err, agent.ErrCircuitOpen) {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{
+			"error": "agent unavailable",
+		})
+		return
+	}
 	if err != nil {
-		_ = s.store.UpdateStatus(evt.ID, types.EventStatusFailed)
-		s.logger.Error("agent forward failed", "error", err, "event_id", evt.ID)
 		writeJSON(w, http.StatusBadGateway, map[string]string{
 			"error": "agent forwarding failed",
 		})
 		return
 	}
 
-	_ = s.store.UpdateStatus(evt.ID, types.EventStatusForwarded)
-
 	writeJSON(w, http.StatusOK, resp)
 }
 
+// forwardContext derives the context for a forward from parent. It carries a
+// deadline covering every attempt allowed by the agent's timeout and retry
+// settings, and is cancelled early if Shutdown gives up waiting.
+func (s *Server) forwardContext(parent context.Context) (context.Context, context.CancelFunc) {
+	var (
+		ctx    context.Context
+		cancel context.CancelFunc
+	)
+	if d := s.forwardTimeout(); d > 0 {
+		ctx, cancel = context.WithTimeout(parent, d)
+	} else {
+		ctx, cancel = context.WithCancel(parent)
+	}
+
+	stop := context.AfterFunc(s.baseCtx, cancel)
+	return ctx, func() {
+		stop()
+		cancel()
+	}
+}
+
+// forwardTimeout returns the overall deadline for one forward: agent.timeout
+// for each attempt plus the longest possible backoff between them.
+func (s *Server) forwardTimeout() time.Duration {
+	a := s.cfg.Agent
+	if a.Timeout <= 0 {
+		return 0
+	}
+	attempts := max(a.Retry.MaxAttempts, 1)
+	return time.Duration(attempts)*a.Timeout + time.Duration(attempts-1)*a.Retry.MaxBackoff
+}
+
+// endStage ends a pipeline stage span, marking it failed if err is set.
+func endStage(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// forward wraps an event, with the channel's forward redaction applied, in
+// an envelope, sends it to the agent, and records the resulting status,
+// attempt count, error and agent response in the event's history. The
+// request ID and trace context in ctx, if any, travel with the envelope.
+// replay marks a re-send of a stored event in both the envelope and history.
+//
+// With a dead-letter queue, an event that fails every attempt is moved there
+// unless the forward was cancelled or refused by the circuit breaker, and one
+// that succeeds is removed from it.
+func (s *Server) forward(ctx context.Context, evt types.Event, replay bool) (agent.Response, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "forward", trace.WithAttributes(
+		attribute.String("gateway.channel", evt.ChannelID),
+		attribute.String("gateway.event_id", evt.ID.String()),
+		attribute.Bool("gateway.replay", replay),
+	))
+	defer span.End()
+
+	envelope := types.EventEnvelope{
+		Version:   "1",
+		Event:     s.bodies[evt.ChannelID].forward.ApplyEvent(evt),
+		Channel:   evt.ChannelID,
+		Skills:    s.skills,
+		Timestamp: time.Now(),
+		RequestID: RequestIDFromContext(ctx),
+		Replay:    replay,
+	}
+	envelope.TraceParent, envelope.TraceState = tracing.TraceParent(ctx)
+
+	start := time.Now()
+	resp, err := s.agent.Forward(ctx, envelope)
+	elapsed := time.Since(start)
+	s.metrics.Forward(evt.ChannelID, elapsed, err)
+
+	change := types.StatusChange{
+		Status:   types.EventStatusForwarded,
+		Attempt:  agent.Attempts(resp, err),
+		Response: resp.Body,
+		Replay:   replay,
+	}
+	span.SetAttributes(attribute.Int("gateway.attempts", change.Attempt))
+	if err != nil {
+		change.Status = s.failureStatus(err)
+		change.Error = err.Error()
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	_ = s.store.Transition(evt.ID, change)
+	s.stats.forwarded(evt.ChannelID, elapsed, change.Status, err)
+
+	if s.dead != nil {
+		if change.Status == types.EventStatusDeadLettered {
+			s.deadLetter(evt, change)
+		} else if err == nil {
+			_ = s.dead.Delete(evt.ID)
+		}
+	}
+
+	if err != nil {
+		if errors.Is(err, agent.ErrCircuitOpen) {
+			s.logger.Warn("agent forward rejected by circuit breaker", "event_id", evt.ID)
+		} else {
+			s.logger.Error("agent forward failed", "error", err, "event_id", evt.ID)
+		}
+		return resp, err
+	}
+	return resp, nil
+}
+
+// failureStatus returns the status of an event whose forward failed with err.
+// Forwards that never reached the agent, because they were cancelled or the
+// circuit was open, leave the event failed rather than dead-lettered.
+func (s *Server) failureStatus(err error) types.EventStatus {
+	if s.dead != nil && !errors.Is(err, context.Canceled) && !errors.Is(err, agent.ErrCircuitOpen) {
+		return types.EventStatusDeadLettered
+	}
+	return types.EventStatusFailed
+}
+
+// fail marks an event failed without forwarding it, recording why.
+func (s *Server) fail(evt types.Event, reason string) {
+	_ = s.store.Transition(evt.ID, types.StatusChange{Status: types.EventStatusFailed, Error: reason})
+	s.stats.failed(evt.ChannelID, reason)
+}
+
 // handleHealth responds to GET /health with a simple liveness check.
 func (s *Server) handleHealth(w http.ResponseWriter, _ *http.Request) {
 	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
 }
 
-// handleAdminEvents responds to GET /admin/events with recent events.
-func (s *Server) handleAdminEvents(w http.ResponseWriter, _ *http.Request) {
-	events, err := s.store.List(50, 0)
+// handleAdminEvents responds to GET /admin/events with events matching the
+// query parameters, newest-first. See parseEventQuery for the filters.
+func (s *Server) handleAdminEvents(w http.ResponseWriter, r *http.Request) {
+	q, err := parseEventQuery(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	page, err := s.store.Query(q)
+	if errors.Is(err, event.ErrInvalidCursor) {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
 	if err != nil {
 		writeJSON(w, http.StatusInternalServerError, map[string]string{
 			"error": "failed to list events",
 		})
 		return
 	}
+
+	resp := map[string]any{
+		"events": page.Events,
+		"count":  len(page.Events),
+	}
+	if page.NextCursor != "" {
+		resp["next_cursor"] = page.NextCursor
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// handleAdminEvent responds to GET /admin/events/{id} with the event and
+// its status history.
+func (s *Server) handleAdminEvent(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid event id"})
+		return
+	}
+
+	evt, err := s.store.Get(id)
+	if errors.Is(err, event.ErrNotFound) {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "event not found"})
+		return
+	}
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get event"})
+		return
+	}
+
+	history, err := s.store.History(id)
+	if err != nil && !errors.Is(err, event.ErrNotFound) {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get event history"})
+		return
+	}
+
 	writeJSON(w, http.StatusOK, map[string]any{
-		"events": events,
-		"count":  len(events),
+		"event":   evt,
+		"history": history,
 	})
 }
 
+// parseEventQuery builds an event query from request parameters:
+// channel, status, since, until, header, body, cursor and limit.
+// since and until accept RFC 3339 timestamps or a duration ago, like "1h".
+func parseEventQuery(r *http.Request) (event.Query, error) {
+	params := r.URL.Query()
+	q := event.Query{
+		ChannelID:      params.Get("channel"),
+		Status:         types.EventStatus(params.Get("status")),
+		HeaderContains: params.Get("header"),
+		BodyContains:   params.Get("body"),
+		Cursor:         params.Get("cursor"),
+	}
+
+	var err error
+	if q.Since, err = parseQueryTime(params.Get("since")); err != nil {
+		return event.Query{}, fmt.Errorf("invalid since: %w", err)
+	}
+	if q.Until, err = parseQueryTime(params.Get("until")); err != nil {
+		return event.Query{}, fmt.Errorf("invalid until: %w", err)
+	}
+	if v := params.Get("limit"); v != "" {
+		if q.Limit, err = strconv.Atoi(v); err != nil || q.Limit <= 0 {
+			return event.Query{}, fmt.Errorf("invalid limit %q: must be a positive integer", v)
+		}
+	}
+	return q, nil
+}
+
+// parseQueryTime parses an RFC 3339 timestamp, or a duration meaning that
+// long before now. An empty value yields the zero time.
+func parseQueryTime(v string) (time.Time, error) {
+	if v == "" {
+		return time.Time{}, nil
+	}
+	if d, err := time.ParseDuration(v); err == nil {
+		return time.Now().Add(-d), nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is neither an RFC 3339 time nor a duration", v)
+	}
+	return t, nil
+}
+
 // handleAdminChannels responds to GET /admin/channels with configured channels.
 func (s *Server) handleAdminChannels(w http.ResponseWriter, _ *http.Request) {
 	names := make([]string, 0, len(s.channels))
@@ -181,6 +668,18 @@ func (s *Server) handleAdminSkills(w http.ResponseWriter, _ *http.Request) {
 	})
 }
 
+// handleAdminBreaker responds to GET /admin/breaker with the agent circuit breaker state.
+func (s *Server) handleAdminBreaker(w http.ResponseWriter, _ *http.Request) {
+	if s.breaker == nil {
+		writeJSON(w, http.StatusOK, map[string]any{"enabled": false})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"enabled": true,
+		"breaker": s.breaker.Snapshot(),
+	})
+}
+
 func writeJSON(w http.ResponseWriter, status int, v any) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
@@ -1,32 +1,55 @@
 package server
 
 import (
+	"bufio"
 	"bytes"
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"log/slog"
+	"maps"
 	"net/http"
 	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/youmna-rabie/claude-pod/internal/agent"
+	"github.com/youmna-rabie/claude-pod/internal/channel"
 	"github.com/youmna-rabie/claude-pod/internal/config"
 	"github.com/youmna-rabie/claude-pod/internal/event"
+	"github.com/youmna-rabie/claude-pod/internal/metrics"
+	"github.com/youmna-rabie/claude-pod/internal/redact"
 	"github.com/youmna-rabie/claude-pod/internal/types"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 )
 
 // testSetup creates a Server with a dummy channel, stub agent, and in-memory store.
 func testSetup(t *testing.T) *Server {
 	t.Helper()
+	return testSetupWithAgent(t, &agent.StubClient{Logger: slog.Default()})
+}
+
+// testSetupWithAgent creates a Server like testSetup but with the given agent client.
+func testSetupWithAgent(t *testing.T, agentClient agent.Client) *Server {
+	t.Helper()
 
 	cfg := &config.Config{
 		Server: config.ServerConfig{Host: "127.0.0.1", Port: 0},
 	}
 
-	store, err := event.NewMemoryStore(100)
-	if err != nil {
-		t.Fatal(err)
-	}
+	store := mustMemoryStore(t)
 
 	logger := slog.Default()
 
@@ -34,8 +57,6 @@ func testSetup(t *testing.T) *Server {
 		"dummy": &dummyTestChannel{name: "dummy"},
 	}
 
-	agentClient := &agent.StubClient{Logger: logger}
-
 	skills := []types.Skill{
 		{Name: "echo", Description: "echoes input", Path: "/skills/echo"},
 	}
@@ -43,6 +64,15 @@ func testSetup(t *testing.T) *Server {
 	return NewServer(cfg, store, channels, agentClient, skills, logger)
 }
 
+func mustMemoryStore(t *testing.T) *event.MemoryStore {
+	t.Helper()
+	store, err := event.NewMemoryStore(100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return store
+}
+
 // dummyTestChannel is a minimal Channel for testing that accepts any POST with a body.
 type dummyTestChannel struct {
 	name string
@@ -76,6 +106,17 @@ type validationError struct{ msg string }
 
 func (e *validationError) Error() string { return e.msg }
 
+// failingAgent is an agent.Client whose Forward always returns err.
+type failingAgent struct {
+	err   error
+	calls int
+}
+
+func (f *failingAgent) Forward(context.Context, types.EventEnvelope) (agent.Response, error) {
+	f.calls++
+	return agent.Response{}, f.err
+}
+
 // --- Health endpoint ---
 
 func TestHealthEndpoint(t *testing.T) {
@@ -135,6 +176,287 @@ func TestWebhookPipeline(t *testing.T) {
 	}
 }
 
+func TestWebhookAgentFailureRecordsAttempts(t *testing.T) {
+	failing := &failingAgent{err: &agent.StatusError{StatusCode: http.StatusServiceUnavailable}}
+	policy := agent.RetryPolicy{MaxAttempts: 3, BaseBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+	srv := testSetupWithAgent(t, agent.NewRetryClient(failing, policy, slog.Default()))
+
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/dummy", bytes.NewBufferString(`{}`))
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadGateway {
+		t.Fatalf("expected 502, got %d", rec.Code)
+	}
+	if failing.calls != 3 {
+		t.Fatalf("expected 3 forward attempts, got %d", failing.calls)
+	}
+
+	events, _ := srv.store.List(10, 0)
+	if len(events) != 1 {
+		t.Fatalf("expected 1 stored event, got %d", len(events))
+	}
+	if events[0].Status != types.EventStatusFailed {
+		t.Fatalf("expected status failed, got %s", events[0].Status)
+	}
+	if events[0].Attempts != 3 {
+		t.Fatalf("expected 3 recorded attempts, got %d", events[0].Attempts)
+	}
+
+	// A forward refused by the open breaker made no attempt.
+	srv = testSetupWithAgent(t, &failingAgent{err: agent.ErrCircuitOpen})
+	req = httptest.NewRequest(http.MethodPost, "/webhooks/dummy", bytes.NewBufferString(`{}`))
+	srv.ServeHTTP(httptest.NewRecorder(), req)
+	events, _ = srv.store.List(10, 0)
+	if len(events) != 1 || events[0].Attempts != 0 {
+		t.Fatalf("circuit-open forward: events = %+v, want 1 with no recorded attempts", events)
+	}
+	detail, _ := srv.store.History(events[0].ID)
+	if last := detail[len(detail)-1]; last.Attempt != 0 {
+		t.Errorf("circuit-open forward: history attempt = %d, want 0", last.Attempt)
+	}
+}
+
+func TestWebhookCircuitOpenFailsFast(t *testing.T) {
+	failing := &failingAgent{err: &agent.StatusError{StatusCode: http.StatusBadGateway}}
+	breaker := agent.NewBreaker(failing, agent.BreakerPolicy{FailureThreshold: 1, Cooldown: time.Hour}, slog.Default())
+	srv := testSetupWithAgent(t, breaker)
+
+	codes := make([]int, 2)
+	for i := range codes {
+		req := httptest.NewRequest(http.MethodPost, "/webhooks/dummy", bytes.NewBufferString(`{}`))
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, req)
+		codes[i] = rec.Code
+	}
+
+	if codes[0] != http.StatusBadGateway {
+		t.Fatalf("first request: expected 502, got %d", codes[0])
+	}
+	if codes[1] != http.StatusServiceUnavailable {
+		t.Fatalf("second request: expected 503 while open, got %d", codes[1])
+	}
+	if failing.calls != 1 {
+		t.Fatalf("expected 1 agent call, got %d", failing.calls)
+	}
+}
+
+// blockingAgent is an agent.Client whose Forward blocks until release is closed.
+type blockingAgent struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func newBlockingAgent() *blockingAgent {
+	return &blockingAgent{started: make(chan struct{}, 16), release: make(chan struct{})}
+}
+
+func (b *blockingAgent) Forward(ctx context.Context, envelope types.EventEnvelope) (agent.Response, error) {
+	b.started <- struct{}{}
+	select {
+	case <-b.release:
+		return agent.Response{Status: "ok", EventID: envelope.Event.ID.String()}, nil
+	case <-ctx.Done():
+		return agent.Response{}, ctx.Err()
+	}
+}
+
+// recordingAgent is an agent.Client that records the envelopes it receives.
+type recordingAgent struct {
+	mu        sync.Mutex
+	envelopes []types.EventEnvelope
+}
+
+func (a *recordingAgent) Forward(_ context.Context, envelope types.EventEnvelope) (agent.Response, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.envelopes = append(a.envelopes, envelope)
+	return agent.Response{
+		Status:  "ok",
+		EventID: envelope.Event.ID.String(),
+		Body:    json.RawMessage(`{"reply":"done"}`),
+	}, nil
+}
+
+func asyncSetup(t *testing.T, agentClient agent.Client, workers, queueSize int) *Server {
+	t.Helper()
+	cfg := &config.Config{
+		Server:   config.ServerConfig{Workers: workers, QueueSize: queueSize},
+		Channels: []config.ChannelConfig{{Name: "dummy", Type: "dummy", Mode: "async"}},
+	}
+	channels := map[string]types.Channel{"dummy": &dummyTestChannel{name: "dummy"}}
+	return NewServer(cfg, mustMemoryStore(t), channels, agentClient, nil, slog.Default())
+}
+
+func TestWebhookAsyncAccepts(t *testing.T) {
+	srv := asyncSetup(t, &agent.StubClient{Logger: slog.Default()}, 2, 10)
+
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/dummy", bytes.NewBufferString(`{"a":1}`))
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var body map[string]string
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if body["event_id"] == "" {
+		t.Fatal("expected event_id in response")
+	}
+
+	// Shutdown drains the queue, so the event must be forwarded afterwards.
+	if err := srv.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+	events, _ := srv.store.List(10, 0)
+	if len(events) != 1 || events[0].Status != types.EventStatusForwarded {
+		t.Fatalf("expected 1 forwarded event, got %+v", events)
+	}
+}
+
+func TestWebhookAsyncQueueFull(t *testing.T) {
+	blocking := newBlockingAgent()
+	srv := asyncSetup(t, blocking, 1, 1)
+	defer srv.Shutdown(context.Background())
+	defer close(blocking.release)
+
+	post := func() int {
+		req := httptest.NewRequest(http.MethodPost, "/webhooks/dummy", bytes.NewBufferString(`{}`))
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	// First event occupies the only worker, second fills the queue.
+	if code := post(); code != http.StatusAccepted {
+		t.Fatalf("first: expected 202, got %d", code)
+	}
+	<-blocking.started
+	if code := post(); code != http.StatusAccepted {
+		t.Fatalf("second: expected 202, got %d", code)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/dummy", bytes.NewBufferString(`{}`))
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("third: expected 429, got %d", rec.Code)
+	}
+	if rec.Header().Get("Retry-After") == "" {
+		t.Fatal("expected Retry-After header on 429")
+	}
+}
+
+func TestWebhookAsyncAfterShutdown(t *testing.T) {
+	srv := asyncSetup(t, &agent.StubClient{Logger: slog.Default()}, 1, 1)
+	if err := srv.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/dummy", bytes.NewBufferString(`{}`))
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 after shutdown, got %d", rec.Code)
+	}
+}
+
+func TestWebhookPropagatesRequestID(t *testing.T) {
+	recorder := &recordingAgent{}
+	srv := testSetupWithAgent(t, recorder)
+
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/dummy", bytes.NewBufferString(`{}`))
+	req.Header.Set("X-Request-ID", "req-sync")
+	srv.ServeHTTP(httptest.NewRecorder(), req)
+
+	if len(recorder.envelopes) != 1 {
+		t.Fatalf("expected 1 forwarded envelope, got %d", len(recorder.envelopes))
+	}
+	if got := recorder.envelopes[0].RequestID; got != "req-sync" {
+		t.Fatalf("envelope request_id = %q, want %q", got, "req-sync")
+	}
+}
+
+func TestWebhookAsyncPropagatesRequestID(t *testing.T) {
+	recorder := &recordingAgent{}
+	srv := asyncSetup(t, recorder, 1, 1)
+
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/dummy", bytes.NewBufferString(`{}`))
+	req.Header.Set("X-Request-ID", "req-async")
+	srv.ServeHTTP(httptest.NewRecorder(), req)
+	srv.Shutdown(context.Background())
+
+	if len(recorder.envelopes) != 1 {
+		t.Fatalf("expected 1 forwarded envelope, got %d", len(recorder.envelopes))
+	}
+	if got := recorder.envelopes[0].RequestID; got != "req-async" {
+		t.Fatalf("envelope request_id = %q, want %q", got, "req-async")
+	}
+}
+
+func TestWebhookClientDisconnectCancelsForward(t *testing.T) {
+	blocking := newBlockingAgent()
+	srv := testSetupWithAgent(t, blocking)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/dummy", bytes.NewBufferString(`{}`)).WithContext(ctx)
+	rec := httptest.NewRecorder()
+
+	done := make(chan struct{})
+	go func() {
+		srv.ServeHTTP(rec, req)
+		close(done)
+	}()
+
+	<-blocking.started
+	cancel()
+	<-done
+
+	events, _ := srv.store.List(10, 0)
+	if len(events) != 1 || events[0].Status != types.EventStatusFailed {
+		t.Fatalf("expected 1 failed event after disconnect, got %+v", events)
+	}
+}
+
+func TestShutdownDeadlineFailsInFlightForwards(t *testing.T) {
+	blocking := newBlockingAgent()
+	srv := asyncSetup(t, blocking, 1, 10)
+
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/dummy", bytes.NewBufferString(`{}`))
+	srv.ServeHTTP(httptest.NewRecorder(), req)
+	<-blocking.started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := srv.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Shutdown error = %v, want context.DeadlineExceeded", err)
+	}
+
+	events, _ := srv.store.List(10, 0)
+	if len(events) != 1 || events[0].Status != types.EventStatusFailed {
+		t.Fatalf("expected in-flight event to be marked failed, got %+v", events)
+	}
+}
+
+func TestForwardTimeout(t *testing.T) {
+	srv := testSetup(t)
+	srv.cfg.Agent = config.AgentConfig{
+		Timeout: 10 * time.Second,
+		Retry:   config.RetryConfig{MaxAttempts: 3, MaxBackoff: 5 * time.Second},
+	}
+	if got, want := srv.forwardTimeout(), 40*time.Second; got != want {
+		t.Fatalf("forwardTimeout = %v, want %v", got, want)
+	}
+
+	srv.cfg.Agent = config.AgentConfig{}
+	if got := srv.forwardTimeout(); got != 0 {
+		t.Fatalf("forwardTimeout without agent.timeout = %v, want 0", got)
+	}
+}
+
 func TestWebhookUnknownChannel(t *testing.T) {
 	srv := testSetup(t)
 
@@ -217,6 +539,184 @@ func TestAdminEventsAfterWebhook(t *testing.T) {
 	}
 }
 
+// seedEvents saves events directly into the server's store, oldest first.
+func seedEvents(t *testing.T, srv *Server, events ...types.Event) {
+	t.Helper()
+	for _, ev := range events {
+		if err := srv.store.Save(ev); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func newTestEvent(channel string, status types.EventStatus, age time.Duration) types.Event {
+	return types.Event{
+		ID:        uuid.New(),
+		ChannelID: channel,
+		RawBody:   json.RawMessage(`{}`),
+		Headers:   map[string]string{},
+		Timestamp: time.Now().Add(-age),
+		Status:    status,
+	}
+}
+
+type eventsResponse struct {
+	Events     []types.Event `json:"events"`
+	Count      int           `json:"count"`
+	NextCursor string        `json:"next_cursor"`
+}
+
+func getEvents(t *testing.T, srv *Server, query string) eventsResponse {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/admin/events?"+query, nil)
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET /admin/events?%s = %d %s", query, rec.Code, rec.Body.String())
+	}
+	var body eventsResponse
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	return body
+}
+
+func TestAdminEventsFilters(t *testing.T) {
+	srv := testSetup(t)
+	oldFailure := newTestEvent("grafana", types.EventStatusFailed, 2*time.Hour)
+	recentFailure := newTestEvent("grafana", types.EventStatusFailed, time.Minute)
+	forwarded := newTestEvent("grafana", types.EventStatusForwarded, time.Minute)
+	slack := newTestEvent("slack", types.EventStatusFailed, time.Minute)
+	seedEvents(t, srv, oldFailure, recentFailure, forwarded, slack)
+
+	body := getEvents(t, srv, "channel=grafana&status=failed&since=1h")
+	if body.Count != 1 || body.Events[0].ID != recentFailure.ID {
+		t.Errorf("failed grafana events in the last hour = %+v, want only %v", body.Events, recentFailure.ID)
+	}
+
+	until := time.Now().Add(-time.Hour).Format(time.RFC3339)
+	body = getEvents(t, srv, "until="+until)
+	if body.Count != 1 || body.Events[0].ID != oldFailure.ID {
+		t.Errorf("events until %s = %+v, want only %v", until, body.Events, oldFailure.ID)
+	}
+}
+
+func TestAdminEventsCursorPagination(t *testing.T) {
+	srv := testSetup(t)
+	for i := 0; i < 5; i++ {
+		seedEvents(t, srv, newTestEvent("dummy", types.EventStatusReceived, 0))
+	}
+
+	seen := 0
+	query := "limit=2"
+	for pages := 0; ; pages++ {
+		if pages > 5 {
+			t.Fatal("pagination did not terminate")
+		}
+		body := getEvents(t, srv, query)
+		seen += body.Count
+		if body.NextCursor == "" {
+			break
+		}
+		query = "limit=2&cursor=" + body.NextCursor
+	}
+	if seen != 5 {
+		t.Errorf("paged through %d events, want 5", seen)
+	}
+}
+
+func TestAdminEventsInvalidQuery(t *testing.T) {
+	srv := testSetup(t)
+	for _, query := range []string{"since=yesterday", "until=soon", "limit=0", "limit=abc", "cursor=%21%21"} {
+		req := httptest.NewRequest(http.MethodGet, "/admin/events?"+query, nil)
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, req)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", query, rec.Code)
+		}
+	}
+}
+
+type eventDetailResponse struct {
+	Event   types.Event          `json:"event"`
+	History []types.StatusChange `json:"history"`
+}
+
+func getEventDetail(t *testing.T, srv *Server, id string) (int, eventDetailResponse) {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/admin/events/"+id, nil)
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+
+	var body eventDetailResponse
+	if rec.Code == http.StatusOK {
+		if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return rec.Code, body
+}
+
+func TestAdminEventDetail(t *testing.T) {
+	srv := testSetupWithAgent(t, &recordingAgent{})
+
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/dummy", bytes.NewBufferString(`{}`))
+	srv.ServeHTTP(httptest.NewRecorder(), req)
+
+	id := uuid.UUID{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+	code, body := getEventDetail(t, srv, id.String())
+	if code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", code)
+	}
+	if body.Event.ID != id || body.Event.Status != types.EventStatusForwarded {
+		t.Errorf("event = %+v, want forwarded event %v", body.Event, id)
+	}
+	if len(body.History) != 2 {
+		t.Fatalf("history has %d entries, want 2: %+v", len(body.History), body.History)
+	}
+	if body.History[0].Status != types.EventStatusReceived {
+		t.Errorf("history[0].Status = %q, want received", body.History[0].Status)
+	}
+	forwarded := body.History[1]
+	if forwarded.Status != types.EventStatusForwarded || forwarded.Attempt != 1 {
+		t.Errorf("history[1] = %+v, want forwarded after 1 attempt", forwarded)
+	}
+	if string(forwarded.Response) != `{"reply":"done"}` {
+		t.Errorf("history[1].Response = %s, want agent response body", forwarded.Response)
+	}
+}
+
+func TestAdminEventDetailRecordsFailure(t *testing.T) {
+	failing := &failingAgent{err: &agent.StatusError{StatusCode: http.StatusServiceUnavailable, Body: "overloaded"}}
+	policy := agent.RetryPolicy{MaxAttempts: 2, BaseBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+	srv := testSetupWithAgent(t, agent.NewRetryClient(failing, policy, slog.Default()))
+
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/dummy", bytes.NewBufferString(`{}`))
+	srv.ServeHTTP(httptest.NewRecorder(), req)
+
+	_, body := getEventDetail(t, srv, uuid.UUID{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}.String())
+	if len(body.History) != 2 {
+		t.Fatalf("history has %d entries, want 2", len(body.History))
+	}
+	failed := body.History[1]
+	if failed.Status != types.EventStatusFailed || failed.Attempt != 2 {
+		t.Errorf("history[1] = %+v, want failed after 2 attempts", failed)
+	}
+	if !strings.Contains(failed.Error, "overloaded") {
+		t.Errorf("history[1].Error = %q, want agent error", failed.Error)
+	}
+}
+
+func TestAdminEventDetailErrors(t *testing.T) {
+	srv := testSetup(t)
+	if code, _ := getEventDetail(t, srv, "not-a-uuid"); code != http.StatusBadRequest {
+		t.Errorf("invalid id: expected 400, got %d", code)
+	}
+	if code, _ := getEventDetail(t, srv, uuid.New().String()); code != http.StatusNotFound {
+		t.Errorf("unknown id: expected 404, got %d", code)
+	}
+}
+
 func TestAdminChannels(t *testing.T) {
 	srv := testSetup(t)
 
@@ -263,6 +763,51 @@ func TestAdminSkills(t *testing.T) {
 	}
 }
 
+func TestAdminBreakerDisabled(t *testing.T) {
+	srv := testSetup(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/breaker", nil)
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+
+	var body map[string]any
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if body["enabled"] != false {
+		t.Fatalf("expected enabled false, got %v", body["enabled"])
+	}
+}
+
+func TestAdminBreaker(t *testing.T) {
+	failing := &failingAgent{err: &agent.StatusError{StatusCode: http.StatusBadGateway}}
+	breaker := agent.NewBreaker(failing, agent.BreakerPolicy{FailureThreshold: 1, Cooldown: time.Hour}, slog.Default())
+	srv := NewServer(&config.Config{}, mustMemoryStore(t), nil, breaker, nil, slog.Default(), WithBreaker(breaker))
+
+	breaker.Forward(context.Background(), types.EventEnvelope{})
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/breaker", nil)
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	var body struct {
+		Enabled bool                  `json:"enabled"`
+		Breaker agent.BreakerSnapshot `json:"breaker"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if !body.Enabled {
+		t.Fatal("expected enabled true")
+	}
+	if body.Breaker.State != agent.BreakerOpen {
+		t.Fatalf("expected state open, got %s", body.Breaker.State)
+	}
+}
+
 // --- Middleware ---
 
 func TestRequestIDMiddleware(t *testing.T) {
@@ -334,6 +879,7 @@ func TestResponsesAreJSON(t *testing.T) {
 		{http.MethodGet, "/admin/events"},
 		{http.MethodGet, "/admin/channels"},
 		{http.MethodGet, "/admin/skills"},
+		{http.MethodGet, "/admin/breaker"},
 	}
 
 	for _, ep := range endpoints {
@@ -349,3 +895,1399 @@ func TestResponsesAreJSON(t *testing.T) {
 		})
 	}
 }
+
+// --- Replay ---
+
+func postReplay(t *testing.T, srv *Server, path string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, path, nil)
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestReplayEvent(t *testing.T) {
+	recorder := &recordingAgent{}
+	srv := testSetupWithAgent(t, recorder)
+	evt := newTestEvent("grafana", types.EventStatusFailed, time.Minute)
+	seedEvents(t, srv, evt)
+
+	rec := postReplay(t, srv, "/admin/events/"+evt.ID.String()+"/replay")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var result replayResult
+	if err := json.NewDecoder(rec.Body).Decode(&result); err != nil {
+		t.Fatal(err)
+	}
+	if result.EventID != evt.ID || result.Status != types.EventStatusForwarded {
+		t.Errorf("result = %+v, want forwarded %v", result, evt.ID)
+	}
+
+	if len(recorder.envelopes) != 1 {
+		t.Fatalf("agent received %d envelopes, want 1", len(recorder.envelopes))
+	}
+	envelope := recorder.envelopes[0]
+	if !envelope.Replay || envelope.Event.ID != evt.ID || envelope.Channel != "grafana" {
+		t.Errorf("envelope = %+v, want replay of %v on grafana", envelope, evt.ID)
+	}
+	if len(envelope.Skills) != 1 {
+		t.Errorf("envelope carries %d skills, want the current 1", len(envelope.Skills))
+	}
+
+	history, _ := srv.store.History(evt.ID)
+	last := history[len(history)-1]
+	if !last.Replay || last.Status != types.EventStatusForwarded {
+		t.Errorf("last history entry = %+v, want forwarded replay", last)
+	}
+}
+
+func TestReplayEventAgentFailure(t *testing.T) {
+	srv := testSetupWithAgent(t, &failingAgent{err: &agent.StatusError{StatusCode: http.StatusInternalServerError}})
+	evt := newTestEvent("grafana", types.EventStatusFailed, time.Minute)
+	seedEvents(t, srv, evt)
+
+	rec := postReplay(t, srv, "/admin/events/"+evt.ID.String()+"/replay")
+	if rec.Code != http.StatusBadGateway {
+		t.Fatalf("expected 502, got %d", rec.Code)
+	}
+	var result replayResult
+	json.NewDecoder(rec.Body).Decode(&result)
+	if result.Status != types.EventStatusFailed || result.Error == "" {
+		t.Errorf("result = %+v, want failed with error", result)
+	}
+}
+
+func TestReplayEventErrors(t *testing.T) {
+	srv := testSetup(t)
+	if rec := postReplay(t, srv, "/admin/events/not-a-uuid/replay"); rec.Code != http.StatusBadRequest {
+		t.Errorf("invalid id: expected 400, got %d", rec.Code)
+	}
+	if rec := postReplay(t, srv, "/admin/events/"+uuid.New().String()+"/replay"); rec.Code != http.StatusNotFound {
+		t.Errorf("unknown id: expected 404, got %d", rec.Code)
+	}
+}
+
+func TestBulkReplay(t *testing.T) {
+	recorder := &recordingAgent{}
+	srv := testSetupWithAgent(t, recorder)
+	failures := []types.Event{
+		newTestEvent("grafana", types.EventStatusFailed, 3*time.Minute),
+		newTestEvent("grafana", types.EventStatusFailed, 2*time.Minute),
+		newTestEvent("grafana", types.EventStatusFailed, time.Minute),
+	}
+	seedEvents(t, srv, failures[0], newTestEvent("grafana", types.EventStatusForwarded, time.Minute))
+	seedEvents(t, srv, failures[1], newTestEvent("slack", types.EventStatusFailed, time.Minute), failures[2])
+
+	rec := postReplay(t, srv, "/admin/replay?channel=grafana&status=failed")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var body struct {
+		Results   []replayResult `json:"results"`
+		Count     int            `json:"count"`
+		Succeeded int            `json:"succeeded"`
+		Failed    int            `json:"failed"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Count != 3 || body.Succeeded != 3 || body.Failed != 0 {
+		t.Errorf("summary = %d replayed, %d succeeded, %d failed; want 3, 3, 0", body.Count, body.Succeeded, body.Failed)
+	}
+
+	if len(recorder.envelopes) != 3 {
+		t.Fatalf("agent received %d envelopes, want 3", len(recorder.envelopes))
+	}
+	for i, want := range failures {
+		if recorder.envelopes[i].Event.ID != want.ID {
+			t.Errorf("replay %d sent %v, want %v (oldest first)", i, recorder.envelopes[i].Event.ID, want.ID)
+		}
+	}
+	if page := getEvents(t, srv, "channel=grafana&status=failed"); page.Count != 0 {
+		t.Errorf("%d grafana events still failed after replay, want 0", page.Count)
+	}
+}
+
+func TestBulkReplayPagesOldestFirst(t *testing.T) {
+	recorder := &recordingAgent{}
+	srv := testSetupWithAgent(t, recorder)
+	newest := newTestEvent("grafana", types.EventStatusFailed, time.Minute)
+	oldest := newTestEvent("grafana", types.EventStatusFailed, 3*time.Minute)
+	middle := newTestEvent("grafana", types.EventStatusFailed, 2*time.Minute)
+	seedEvents(t, srv, newest, oldest, middle)
+
+	var body struct {
+		Count      int    `json:"count"`
+		NextCursor string `json:"next_cursor"`
+	}
+	rec := postReplay(t, srv, "/admin/replay?status=failed&limit=2")
+	json.NewDecoder(rec.Body).Decode(&body)
+	if rec.Code != http.StatusOK || body.Count != 2 || body.NextCursor == "" {
+		t.Fatalf("first batch = %d %+v, want 2 replays and a next_cursor", rec.Code, body)
+	}
+
+	cursor := body.NextCursor
+	body.NextCursor = ""
+	rec = postReplay(t, srv, "/admin/replay?status=failed&limit=2&cursor="+cursor)
+	json.NewDecoder(rec.Body).Decode(&body)
+	if rec.Code != http.StatusOK || body.Count != 1 || body.NextCursor != "" {
+		t.Fatalf("second batch = %d %+v, want the last replay and no next_cursor", rec.Code, body)
+	}
+
+	for i, want := range []types.Event{oldest, middle, newest} {
+		if i >= len(recorder.envelopes) || recorder.envelopes[i].Event.ID != want.ID {
+			t.Errorf("replay %d did not send %v (oldest first across batches)", i, want.ID)
+		}
+	}
+
+	if rec := postReplay(t, srv, "/admin/replay?status=failed&cursor=bogus"); rec.Code != http.StatusBadRequest {
+		t.Errorf("invalid cursor: expected 400, got %d", rec.Code)
+	}
+}
+
+func TestBulkReplayRequiresFilter(t *testing.T) {
+	srv := testSetup(t)
+	seedEvents(t, srv, newTestEvent("grafana", types.EventStatusFailed, time.Minute))
+
+	for _, path := range []string{"/admin/replay", "/admin/replay?limit=10", "/admin/replay?status=failed&since=whenever"} {
+		if rec := postReplay(t, srv, path); rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", path, rec.Code)
+		}
+	}
+}
+
+// --- Dead-letter queue ---
+
+// deadLetterSetup creates a Server like testSetupWithAgent with a dead-letter queue.
+func deadLetterSetup(t *testing.T, agentClient agent.Client) *Server {
+	t.Helper()
+	cfg := &config.Config{}
+	channels := map[string]types.Channel{"dummy": &dummyTestChannel{name: "dummy"}}
+	return NewServer(cfg, mustMemoryStore(t), channels, agentClient, nil, slog.Default(),
+		WithDeadLetters(mustMemoryStore(t)),
+	)
+}
+
+// switchableAgent fails while failing is set and succeeds otherwise.
+type switchableAgent struct {
+	mu      sync.Mutex
+	failing bool
+}
+
+func (a *switchableAgent) set(failing bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.failing = failing
+}
+
+func (a *switchableAgent) Forward(context.Context, types.EventEnvelope) (agent.Response, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.failing {
+		return agent.Response{}, &agent.StatusError{StatusCode: http.StatusInternalServerError}
+	}
+	return agent.Response{Status: "ok"}, nil
+}
+
+var dummyEventID = uuid.UUID{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+
+func TestWebhookFailureMovesEventToDeadLetters(t *testing.T) {
+	srv := deadLetterSetup(t, &failingAgent{err: &agent.StatusError{StatusCode: http.StatusInternalServerError}})
+
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/dummy", bytes.NewBufferString(`{}`))
+	srv.ServeHTTP(httptest.NewRecorder(), req)
+
+	stored, _ := srv.store.Get(dummyEventID)
+	if stored.Status != types.EventStatusDeadLettered {
+		t.Errorf("stored status = %q, want dead_lettered", stored.Status)
+	}
+	dead, err := srv.dead.Get(dummyEventID)
+	if err != nil {
+		t.Fatalf("event not in dead-letter queue: %v", err)
+	}
+	if dead.Status != types.EventStatusDeadLettered {
+		t.Errorf("dead-letter status = %q, want dead_lettered", dead.Status)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/admin/dead-letters?channel=dummy", nil)
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+	var body eventsResponse
+	json.NewDecoder(rec.Body).Decode(&body)
+	if rec.Code != http.StatusOK || body.Count != 1 || body.Events[0].ID != dummyEventID {
+		t.Errorf("GET /admin/dead-letters = %d %+v, want the dead-lettered event", rec.Code, body)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/admin/dead-letters/"+dummyEventID.String(), nil)
+	rec = httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+	var detail eventDetailResponse
+	json.NewDecoder(rec.Body).Decode(&detail)
+	if rec.Code != http.StatusOK || len(detail.History) != 2 || detail.History[1].Error == "" {
+		t.Errorf("GET /admin/dead-letters/{id} = %d %+v, want history ending in the failure", rec.Code, detail)
+	}
+}
+
+func TestCancelledForwardIsNotDeadLettered(t *testing.T) {
+	srv := deadLetterSetup(t, &failingAgent{err: context.Canceled})
+
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/dummy", bytes.NewBufferString(`{}`))
+	srv.ServeHTTP(httptest.NewRecorder(), req)
+
+	if stored, _ := srv.store.Get(dummyEventID); stored.Status != types.EventStatusFailed {
+		t.Errorf("stored status = %q, want failed", stored.Status)
+	}
+	if srv.dead.Count() != 0 {
+		t.Error("cancelled forward should not be dead-lettered")
+	}
+}
+
+func TestCircuitOpenForwardIsNotDeadLettered(t *testing.T) {
+	srv := deadLetterSetup(t, &failingAgent{err: agent.ErrCircuitOpen})
+
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/dummy", bytes.NewBufferString(`{}`))
+	srv.ServeHTTP(httptest.NewRecorder(), req)
+
+	if stored, _ := srv.store.Get(dummyEventID); stored.Status != types.EventStatusFailed {
+		t.Errorf("stored status = %q, want failed", stored.Status)
+	}
+	if srv.dead.Count() != 0 {
+		t.Error("forward refused by the circuit breaker should not be dead-lettered")
+	}
+}
+
+func TestRequeueDeadLetter(t *testing.T) {
+	switchable := &switchableAgent{failing: true}
+	srv := deadLetterSetup(t, switchable)
+
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/dummy", bytes.NewBufferString(`{}`))
+	srv.ServeHTTP(httptest.NewRecorder(), req)
+	path := "/admin/dead-letters/" + dummyEventID.String() + "/requeue"
+
+	// Still failing: the event stays dead-lettered with the attempt recorded.
+	if rec := postReplay(t, srv, path); rec.Code != http.StatusBadGateway {
+		t.Fatalf("failing requeue: expected 502, got %d", rec.Code)
+	}
+	if history, _ := srv.dead.History(dummyEventID); len(history) != 2 || !history[1].Replay {
+		t.Errorf("dead-letter history = %+v, want the failed requeue recorded", history)
+	}
+
+	switchable.set(false)
+	rec := postReplay(t, srv, path)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("requeue: expected 200, got %d", rec.Code)
+	}
+	if _, err := srv.dead.Get(dummyEventID); err != event.ErrNotFound {
+		t.Errorf("requeued event still in dead-letter queue: %v", err)
+	}
+	if stored, _ := srv.store.Get(dummyEventID); stored.Status != types.EventStatusForwarded {
+		t.Errorf("stored status = %q, want forwarded", stored.Status)
+	}
+}
+
+func TestRequeueDeadLetterRestoresEvictedEvent(t *testing.T) {
+	srv := deadLetterSetup(t, &recordingAgent{})
+	evt := newTestEvent("grafana", types.EventStatusDeadLettered, time.Minute)
+	srv.dead.Save(evt)
+
+	if rec := postReplay(t, srv, "/admin/dead-letters/"+evt.ID.String()+"/requeue"); rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if stored, err := srv.store.Get(evt.ID); err != nil || stored.Status != types.EventStatusForwarded {
+		t.Errorf("restored event = %+v, %v; want forwarded", stored, err)
+	}
+}
+
+func TestDiscardDeadLetter(t *testing.T) {
+	srv := deadLetterSetup(t, &failingAgent{err: &agent.StatusError{StatusCode: http.StatusInternalServerError}})
+
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/dummy", bytes.NewBufferString(`{}`))
+	srv.ServeHTTP(httptest.NewRecorder(), req)
+
+	req = httptest.NewRequest(http.MethodDelete, "/admin/dead-letters/"+dummyEventID.String(), nil)
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if srv.dead.Count() != 0 {
+		t.Error("discarded event still in dead-letter queue")
+	}
+	if stored, _ := srv.store.Get(dummyEventID); stored.Status != types.EventStatusFailed {
+		t.Errorf("stored status = %q, want failed after discard", stored.Status)
+	}
+
+	rec = httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/admin/dead-letters/"+dummyEventID.String(), nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("second discard: expected 404, got %d", rec.Code)
+	}
+}
+
+func TestDeadLettersDisabled(t *testing.T) {
+	srv := testSetup(t)
+	for _, path := range []string{"/admin/dead-letters", "/admin/dead-letters/" + dummyEventID.String()} {
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("%s without a dead-letter queue: expected 404, got %d", path, rec.Code)
+		}
+	}
+}
+
+type streamMessage struct {
+	id, event string
+	data      event.Notification
+}
+
+// streamServer serves srv over HTTP, ending open streams before the
+// listener is closed so the test does not wait for them.
+func streamServer(t *testing.T, srv *Server) *httptest.Server {
+	t.Helper()
+	ts := httptest.NewServer(srv)
+	t.Cleanup(ts.Close)
+	t.Cleanup(srv.CloseStreams)
+	return ts
+}
+
+// openStream connects to the event stream of a server started with
+// httptest.NewServer. The connection is closed when the test ends.
+func openStream(t *testing.T, ts *httptest.Server, query, lastID string) *bufio.Reader {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	t.Cleanup(cancel)
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL+"/admin/events/stream?"+query, nil)
+	if lastID != "" {
+		req.Header.Set("Last-Event-ID", lastID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("stream status = %d", resp.StatusCode)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Content-Type = %q, want text/event-stream", ct)
+	}
+	return bufio.NewReader(resp.Body)
+}
+
+// readStreamMessage returns the next message with data, skipping comments
+// and the retry hint.
+func readStreamMessage(t *testing.T, r *bufio.Reader) streamMessage {
+	t.Helper()
+	var msg streamMessage
+	var data string
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("reading stream: %v", err)
+		}
+		line = strings.TrimSuffix(line, "\n")
+		field, value, _ := strings.Cut(line, ": ")
+		switch {
+		case line == "" && data != "":
+			if err := json.Unmarshal([]byte(data), &msg.data); err != nil {
+				t.Fatalf("decoding %q: %v", data, err)
+			}
+			return msg
+		case field == "id":
+			msg.id = value
+		case field == "event":
+			msg.event = value
+		case field == "data":
+			data = value
+		}
+	}
+}
+
+func TestEventStreamPublishesEventsAndStatusChanges(t *testing.T) {
+	srv := testSetup(t)
+	ts := streamServer(t, srv)
+	stream := openStream(t, ts, "", "")
+
+	resp, err := http.Post(ts.URL+"/webhooks/dummy", "application/json", strings.NewReader(`{"alert":"cpu"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	msg := readStreamMessage(t, stream)
+	if msg.event != "event" || msg.data.Type != event.NotifySaved || msg.data.Event.ChannelID != "dummy" {
+		t.Fatalf("first message = %+v", msg)
+	}
+	if msg.id != msg.data.Event.ID.String() {
+		t.Errorf("id = %q, want event ID %s", msg.id, msg.data.Event.ID)
+	}
+
+	msg = readStreamMessage(t, stream)
+	if msg.event != "status" || msg.id != "" {
+		t.Fatalf("second message = %+v, want status change without id", msg)
+	}
+	if msg.data.Change == nil || msg.data.Change.Status != types.EventStatusForwarded {
+		t.Errorf("status change = %+v, want forwarded", msg.data.Change)
+	}
+}
+
+func TestEventStreamFilters(t *testing.T) {
+	srv := testSetup(t)
+	ts := streamServer(t, srv)
+	stream := openStream(t, ts, "channel=grafana&status=failed", "")
+
+	other := newTestEvent("dummy", types.EventStatusReceived, 0)
+	wanted := newTestEvent("grafana", types.EventStatusReceived, 0)
+	seedEvents(t, srv, other, wanted)
+	srv.store.Transition(other.ID, types.StatusChange{Status: types.EventStatusFailed})
+	srv.store.Transition(wanted.ID, types.StatusChange{Status: types.EventStatusForwarded})
+	srv.store.Transition(wanted.ID, types.StatusChange{Status: types.EventStatusFailed, Error: "boom"})
+
+	msg := readStreamMessage(t, stream)
+	if msg.data.Event.ID != wanted.ID || msg.data.Change == nil || msg.data.Change.Error != "boom" {
+		t.Errorf("filtered stream delivered %+v, want only the grafana failure", msg.data)
+	}
+}
+
+func TestEventStreamResumesFromLastEventID(t *testing.T) {
+	srv := testSetup(t)
+	ts := streamServer(t, srv)
+
+	seen := newTestEvent("dummy", types.EventStatusForwarded, 3*time.Minute)
+	missed := []types.Event{
+		newTestEvent("dummy", types.EventStatusFailed, 2*time.Minute),
+		newTestEvent("dummy", types.EventStatusForwarded, time.Minute),
+	}
+	seedEvents(t, srv, seen, missed[0], missed[1])
+
+	stream := openStream(t, ts, "", seen.ID.String())
+	for i, want := range missed {
+		msg := readStreamMessage(t, stream)
+		if msg.data.Event.ID != want.ID || msg.id != want.ID.String() {
+			t.Fatalf("resumed message %d = %s, want %s", i, msg.data.Event.ID, want.ID)
+		}
+		if msg.data.Event.Status != want.Status {
+			t.Errorf("resumed event %d status = %s, want current status %s", i, msg.data.Event.Status, want.Status)
+		}
+	}
+
+	live := newTestEvent("dummy", types.EventStatusReceived, 0)
+	seedEvents(t, srv, live)
+	if msg := readStreamMessage(t, stream); msg.data.Event.ID != live.ID {
+		t.Errorf("live message after resume = %s, want %s", msg.data.Event.ID, live.ID)
+	}
+}
+
+func TestEventStreamInvalidLastEventID(t *testing.T) {
+	srv := testSetup(t)
+	req := httptest.NewRequest(http.MethodGet, "/admin/events/stream", nil)
+	req.Header.Set("Last-Event-ID", "not-a-uuid")
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", rec.Code)
+	}
+}
+
+func TestCloseStreamsEndsStreams(t *testing.T) {
+	srv := testSetup(t)
+	ts := streamServer(t, srv)
+	stream := openStream(t, ts, "", "")
+
+	srv.CloseStreams()
+	if _, err := io.ReadAll(stream); err != nil {
+		t.Errorf("stream did not end cleanly: %v", err)
+	}
+	srv.CloseStreams()
+}
+
+// rejectingTestChannel fails validation or parsing depending on the request.
+type rejectingTestChannel struct {
+	dummyTestChannel
+}
+
+func (c *rejectingTestChannel) ValidateRequest(r *http.Request) error {
+	if r.Header.Get("X-Reject") != "" {
+		return &validationError{msg: "rejected"}
+	}
+	return nil
+}
+
+func (c *rejectingTestChannel) ParseRequest(r *http.Request) (*types.Event, error) {
+	if r.ContentLength == 0 {
+		return nil, errors.New("empty body")
+	}
+	return c.dummyTestChannel.ParseRequest(r)
+}
+
+func TestMetricsEndpoint(t *testing.T) {
+	m := metrics.New()
+	store := mustMemoryStore(t)
+	m.RegisterStore("events", store)
+
+	cfg := &config.Config{Server: config.ServerConfig{Host: "127.0.0.1"}}
+	channels := map[string]types.Channel{
+		"strict": &rejectingTestChannel{dummyTestChannel{name: "strict"}},
+	}
+	agentClient := &failingAgent{err: &agent.StatusError{StatusCode: http.StatusInternalServerError}}
+	srv := NewServer(cfg, store, channels, agentClient, nil, slog.Default(), WithMetrics(m))
+
+	post := func(body string, reject bool) {
+		req := httptest.NewRequest(http.MethodPost, "/webhooks/strict", strings.NewReader(body))
+		if reject {
+			req.Header.Set("X-Reject", "1")
+		}
+		srv.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	post(`{"a":1}`, true)
+	post("", false)
+	post(`{"a":1}`, false)
+
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET /metrics status = %d", rec.Code)
+	}
+	body := rec.Body.String()
+	for _, want := range []string{
+		`gateway_webhook_requests_total{channel="strict",code="400"} 2`,
+		`gateway_webhook_requests_total{channel="strict",code="502"} 1`,
+		`gateway_webhook_rejected_total{channel="strict",stage="validation"} 1`,
+		`gateway_webhook_rejected_total{channel="strict",stage="parse"} 1`,
+		`gateway_agent_forward_errors_total{channel="strict",reason="agent_status"} 1`,
+		`gateway_agent_forward_duration_seconds_count{channel="strict",outcome="error"} 1`,
+		`gateway_http_requests_total{code="400",method="POST",route="/webhooks/{channel}"} 2`,
+		`gateway_http_requests_in_flight 1`,
+		`gateway_store_events{store="events"} 1`,
+		`gateway_store_evictions_total{store="events"} 0`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics missing %s", want)
+		}
+	}
+}
+
+func TestMetricsEndpointDisabled(t *testing.T) {
+	srv := testSetup(t)
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("GET /metrics without metrics = %d, want 404", rec.Code)
+	}
+}
+
+// recordSpans installs a tracer provider that keeps finished spans in memory
+// for the duration of the test.
+func recordSpans(t *testing.T) *tracetest.InMemoryExporter {
+	t.Helper()
+	exp := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exp))
+	old := otel.GetTracerProvider()
+	otel.SetTracerProvider(tp)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(old)
+		tp.Shutdown(context.Background())
+	})
+	return exp
+}
+
+func TestWebhookTracesPipelineStages(t *testing.T) {
+	exp := recordSpans(t)
+	rec := &recordingAgent{}
+	srv := testSetupWithAgent(t, rec)
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/dummy", strings.NewReader(`{"a":1}`))
+	req.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	w := httptest.NewRecorder()
+	srv.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("webhook status = %d", w.Code)
+	}
+
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	for _, s := range exp.GetSpans().Snapshots() {
+		spans[s.Name()] = s
+	}
+	root, ok := spans["POST /webhooks/{channel}"]
+	if !ok {
+		t.Fatalf("no webhook span; got %v", slices.Collect(maps.Keys(spans)))
+	}
+	if root.Parent().SpanID().String() != "00f067aa0ba902b7" {
+		t.Errorf("webhook span parent = %s, want the incoming span", root.Parent().SpanID())
+	}
+	for _, name := range []string{"validate", "parse", "store", "forward"} {
+		s, ok := spans[name]
+		if !ok {
+			t.Errorf("missing %s span", name)
+			continue
+		}
+		if s.SpanContext().TraceID().String() != traceID {
+			t.Errorf("%s span trace = %s, want %s", name, s.SpanContext().TraceID(), traceID)
+		}
+		if s.Parent().SpanID() != root.SpanContext().SpanID() {
+			t.Errorf("%s span is not a child of the webhook span", name)
+		}
+	}
+
+	forward := spans["forward"]
+	want := "00-" + traceID + "-" + forward.SpanContext().SpanID().String() + "-01"
+	if got := rec.envelopes[0].TraceParent; got != want {
+		t.Errorf("envelope traceparent = %q, want %q", got, want)
+	}
+}
+
+func TestAsyncForwardContinuesWebhookTrace(t *testing.T) {
+	exp := recordSpans(t)
+	rec := &recordingAgent{}
+	srv := asyncSetup(t, rec, 1, 10)
+
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/dummy", strings.NewReader(`{"a":1}`))
+	srv.ServeHTTP(httptest.NewRecorder(), req)
+	if err := srv.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	var root, forward sdktrace.ReadOnlySpan
+	for _, s := range exp.GetSpans().Snapshots() {
+		switch s.Name() {
+		case "POST /webhooks/{channel}":
+			root = s
+		case "forward":
+			forward = s
+		}
+	}
+	if root == nil || forward == nil {
+		t.Fatal("missing webhook or forward span")
+	}
+	if forward.Parent().SpanID() != root.SpanContext().SpanID() {
+		t.Error("async forward span is not a child of the webhook span")
+	}
+}
+
+type statsResponse struct {
+	UptimeSeconds float64        `json:"uptime_seconds"`
+	Events        map[string]int `json:"events"`
+	Totals        map[string]int `json:"totals"`
+	Channels      map[string]struct {
+		Statuses           map[string]int `json:"statuses"`
+		ValidationFailures int            `json:"validation_failures"`
+		ParseFailures      int            `json:"parse_failures"`
+		RateLimited        int            `json:"rate_limited"`
+		LastEventAt        *time.Time     `json:"last_event_at"`
+		LastError          string         `json:"last_error"`
+	} `json:"channels"`
+	Agent struct {
+		Forwards          int     `json:"forwards"`
+		Succeeded         int     `json:"succeeded"`
+		Failed            int     `json:"failed"`
+		SuccessRate       float64 `json:"success_rate"`
+		CircuitRejections int     `json:"circuit_rejections"`
+		LatencyMS         struct {
+			P50     float64 `json:"p50"`
+			P95     float64 `json:"p95"`
+			Samples int     `json:"samples"`
+		} `json:"latency_ms"`
+	} `json:"agent"`
+}
+
+func getStats(t *testing.T, srv *Server) statsResponse {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/stats", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET /admin/stats status = %d", rec.Code)
+	}
+	var resp statsResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	return resp
+}
+
+func TestAdminStats(t *testing.T) {
+	switchable := &switchableAgent{}
+	cfg := &config.Config{Server: config.ServerConfig{Host: "127.0.0.1"}}
+	channels := map[string]types.Channel{
+		"strict": &rejectingTestChannel{dummyTestChannel{name: "strict"}},
+		"idle":   &dummyTestChannel{name: "idle"},
+	}
+	srv := NewServer(cfg, mustMemoryStore(t), channels, switchable, nil, slog.Default())
+
+	post := func(body string, reject bool) {
+		req := httptest.NewRequest(http.MethodPost, "/webhooks/strict", strings.NewReader(body))
+		if reject {
+			req.Header.Set("X-Reject", "1")
+		}
+		srv.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	post(`{"a":1}`, false)
+	switchable.set(true)
+	post(`{"a":2}`, false)
+	post(`{"a":3}`, true)
+	post("", false)
+
+	stats := getStats(t, srv)
+	strict := stats.Channels["strict"]
+	if strict.Statuses["received"] != 2 || strict.Statuses["forwarded"] != 1 || strict.Statuses["failed"] != 1 {
+		t.Errorf("strict statuses = %v, want 2 received, 1 forwarded, 1 failed", strict.Statuses)
+	}
+	if strict.ValidationFailures != 1 || strict.ParseFailures != 1 {
+		t.Errorf("strict failures = %d validation, %d parse, want 1 each", strict.ValidationFailures, strict.ParseFailures)
+	}
+	if strict.LastEventAt == nil || time.Since(*strict.LastEventAt) > time.Minute {
+		t.Errorf("strict last_event_at = %v, want recent", strict.LastEventAt)
+	}
+	if strict.LastError != "parse: empty body" {
+		t.Errorf("strict last_error = %q, want the parse failure", strict.LastError)
+	}
+
+	idle, ok := stats.Channels["idle"]
+	if !ok || idle.LastEventAt != nil || len(idle.Statuses) != 0 {
+		t.Errorf("idle channel = %+v (present %v), want listed with no events", idle, ok)
+	}
+
+	if stats.Totals["received"] != 2 || stats.Events["stored"] != 2 {
+		t.Errorf("totals = %v, events = %v", stats.Totals, stats.Events)
+	}
+	a := stats.Agent
+	if a.Forwards != 2 || a.Succeeded != 1 || a.Failed != 1 || a.SuccessRate != 0.5 {
+		t.Errorf("agent = %+v, want 2 forwards, 1 succeeded, rate 0.5", a)
+	}
+	if a.LatencyMS.Samples != 2 || a.LatencyMS.P95 < a.LatencyMS.P50 {
+		t.Errorf("agent latency = %+v", a.LatencyMS)
+	}
+	if stats.UptimeSeconds <= 0 {
+		t.Errorf("uptime_seconds = %v, want positive", stats.UptimeSeconds)
+	}
+}
+
+func TestAdminStatsCountsCircuitRejectionsApart(t *testing.T) {
+	srv := testSetupWithAgent(t, &failingAgent{err: agent.ErrCircuitOpen})
+	for i := 0; i < 2; i++ {
+		req := httptest.NewRequest(http.MethodPost, "/webhooks/dummy", strings.NewReader(`{}`))
+		srv.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	stats := getStats(t, srv)
+	a := stats.Agent
+	if a.CircuitRejections != 2 || a.Forwards != 0 || a.SuccessRate != 0 || a.LatencyMS.Samples != 0 {
+		t.Errorf("agent = %+v, want 2 circuit rejections and no forwards or latency samples", a)
+	}
+	if dummy := stats.Channels["dummy"]; dummy.Statuses["failed"] != 2 {
+		t.Errorf("dummy statuses = %v, want 2 failed", dummy.Statuses)
+	}
+}
+
+func TestAdminStatsCountsQueueFullAsFailure(t *testing.T) {
+	blocking := newBlockingAgent()
+	srv := asyncSetup(t, blocking, 1, 1)
+	defer close(blocking.release)
+
+	for i := 0; i < 3; i++ {
+		req := httptest.NewRequest(http.MethodPost, "/webhooks/dummy", strings.NewReader(`{}`))
+		srv.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	dummy := getStats(t, srv).Channels["dummy"]
+	if dummy.Statuses["failed"] < 1 || dummy.LastError != "async queue full" {
+		t.Errorf("dummy = %+v, want a queue-full failure", dummy)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	var samples []time.Duration
+	for i := 1; i <= 100; i++ {
+		samples = append(samples, time.Duration(i)*time.Millisecond)
+	}
+	if p := percentile(samples, 50); p != 50 {
+		t.Errorf("p50 = %v, want 50", p)
+	}
+	if p := percentile(samples, 95); p != 95 {
+		t.Errorf("p95 = %v, want 95", p)
+	}
+	if p := percentile(samples[:1], 95); p != 1 {
+		t.Errorf("p95 of one sample = %v, want 1", p)
+	}
+	if p := percentile(nil, 50); p != 0 {
+		t.Errorf("p50 of nothing = %v, want 0", p)
+	}
+}
+
+// --- Rate limiting ---
+
+func rateLimitedSetup(t *testing.T, limit config.RateLimitConfig) *Server {
+	t.Helper()
+	cfg := &config.Config{
+		Server:   config.ServerConfig{Host: "127.0.0.1"},
+		Channels: []config.ChannelConfig{{Name: "dummy", Type: "dummy", RateLimit: limit}},
+	}
+	channels := map[string]types.Channel{"dummy": &dummyTestChannel{name: "dummy"}}
+	return NewServer(cfg, mustMemoryStore(t), channels, &agent.StubClient{Logger: slog.Default()}, nil, slog.Default())
+}
+
+func postFrom(srv *Server, remoteAddr string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/dummy", strings.NewReader(`{}`))
+	req.RemoteAddr = remoteAddr
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestWebhookRateLimit(t *testing.T) {
+	srv := rateLimitedSetup(t, config.RateLimitConfig{Rate: 0.01, Burst: 2})
+
+	for i := range 2 {
+		if rec := postFrom(srv, "192.0.2.1:1234"); rec.Code != http.StatusOK {
+			t.Fatalf("request %d: expected 200, got %d", i, rec.Code)
+		}
+	}
+	rec := postFrom(srv, "192.0.2.2:1234")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if got := rec.Header().Get("Retry-After"); got == "" || got == "0" {
+		t.Errorf("Retry-After = %q, want a positive number of seconds", got)
+	}
+
+	stats := getStats(t, srv)
+	dummy := stats.Channels["dummy"]
+	if dummy.RateLimited != 1 || dummy.Statuses["received"] != 2 {
+		t.Errorf("dummy = %+v, want 2 received and 1 rate limited", dummy)
+	}
+	if dummy.LastError != "" {
+		t.Errorf("last_error = %q, want rate limiting not to count as an error", dummy.LastError)
+	}
+	if srv.store.Count() != 2 {
+		t.Errorf("stored %d events, want the rejected one not stored", srv.store.Count())
+	}
+}
+
+func TestWebhookRateLimitPerIP(t *testing.T) {
+	srv := rateLimitedSetup(t, config.RateLimitConfig{Rate: 0.01, Burst: 1, PerIP: true})
+
+	if rec := postFrom(srv, "192.0.2.1:1234"); rec.Code != http.StatusOK {
+		t.Fatalf("first request from .1: expected 200, got %d", rec.Code)
+	}
+	if rec := postFrom(srv, "192.0.2.1:5678"); rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("second request from .1: expected 429, got %d", rec.Code)
+	}
+	if rec := postFrom(srv, "192.0.2.2:1234"); rec.Code != http.StatusOK {
+		t.Fatalf("first request from .2: expected 200, got %d", rec.Code)
+	}
+}
+
+func TestRateLimiterIdleTTL(t *testing.T) {
+	tests := []struct {
+		rate  float64
+		burst int
+		want  time.Duration
+	}{
+		{rate: 10, burst: 20, want: idleLimiterTTL},
+		// One token every 1000s: a bucket of 5 takes 5000s to refill, so it
+		// must outlive the default TTL or its IP gets a fresh burst early.
+		{rate: 0.001, burst: 5, want: 5000 * time.Second},
+	}
+	for _, tt := range tests {
+		l := newRateLimiter(config.RateLimitConfig{Rate: tt.rate, Burst: tt.burst, PerIP: true})
+		if l.idleTTL != tt.want {
+			t.Errorf("rate %g burst %d: idleTTL = %s, want %s", tt.rate, tt.burst, l.idleTTL, tt.want)
+		}
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	cases := map[time.Duration]string{
+		time.Millisecond:        "1",
+		time.Second:             "1",
+		1500 * time.Millisecond: "2",
+		90 * time.Second:        "90",
+	}
+	for d, want := range cases {
+		if got := retryAfter(d); got != want {
+			t.Errorf("retryAfter(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
+
+// --- Admin authentication ---
+
+func authSetup(t *testing.T, admin config.AdminConfig) (*Server, *bytes.Buffer) {
+	t.Helper()
+	cfg := &config.Config{Server: config.ServerConfig{Host: "127.0.0.1"}, Admin: admin}
+	channels := map[string]types.Channel{"dummy": &dummyTestChannel{name: "dummy"}}
+	var audit bytes.Buffer
+	srv := NewServer(cfg, mustMemoryStore(t), channels, &agent.StubClient{Logger: slog.Default()}, nil, slog.Default(),
+		WithAuditLog(slog.New(slog.NewJSONHandler(&audit, nil))),
+	)
+	return srv, &audit
+}
+
+var testAdminKeys = []config.AdminKeyConfig{
+	{Name: "dashboard", Key: "view-key", Role: "viewer"},
+	{Name: "oncall", Key: "op-key", Role: "operator"},
+}
+
+func adminRequest(h http.Handler, method, path string, header ...string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	for i := 0; i+1 < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestAdminAuthRequiresKey(t *testing.T) {
+	srv, _ := authSetup(t, config.AdminConfig{Keys: testAdminKeys})
+
+	rec := adminRequest(srv, http.MethodGet, "/admin/events")
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("no key: expected 401, got %d", rec.Code)
+	}
+	if rec.Header().Get("WWW-Authenticate") == "" {
+		t.Error("401 response is missing WWW-Authenticate")
+	}
+	if rec := adminRequest(srv, http.MethodGet, "/admin/events", "Authorization", "Bearer wrong"); rec.Code != http.StatusUnauthorized {
+		t.Errorf("wrong key: expected 401, got %d", rec.Code)
+	}
+	if rec := adminRequest(srv, http.MethodGet, "/admin/events", "Authorization", "Bearer view-key"); rec.Code != http.StatusOK {
+		t.Errorf("bearer token: expected 200, got %d", rec.Code)
+	}
+	if rec := adminRequest(srv, http.MethodGet, "/admin/stats", "X-API-Key", "view-key"); rec.Code != http.StatusOK {
+		t.Errorf("X-API-Key: expected 200, got %d", rec.Code)
+	}
+	if rec := adminRequest(srv, http.MethodGet, "/health"); rec.Code != http.StatusOK {
+		t.Errorf("health: expected 200 without a key, got %d", rec.Code)
+	}
+}
+
+func TestAdminAuthRoles(t *testing.T) {
+	srv, _ := authSetup(t, config.AdminConfig{Keys: testAdminKeys})
+	evt := newTestEvent("dummy", types.EventStatusFailed, time.Minute)
+	seedEvents(t, srv, evt)
+	replay := "/admin/events/" + evt.ID.String() + "/replay"
+
+	rec := adminRequest(srv, http.MethodPost, replay, "Authorization", "Bearer view-key")
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("viewer replay: expected 403, got %d", rec.Code)
+	}
+	rec = adminRequest(srv, http.MethodPost, replay, "Authorization", "Bearer op-key")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("operator replay: expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec := adminRequest(srv, http.MethodGet, "/admin/events", "Authorization", "Bearer op-key"); rec.Code != http.StatusOK {
+		t.Errorf("operator read: expected 200, got %d", rec.Code)
+	}
+}
+
+func TestAdminAuthDisabledWithoutKeys(t *testing.T) {
+	srv, _ := authSetup(t, config.AdminConfig{})
+	if rec := adminRequest(srv, http.MethodPost, "/admin/replay?status=failed"); rec.Code != http.StatusOK {
+		t.Errorf("expected 200 with no keys configured, got %d", rec.Code)
+	}
+}
+
+func TestAdminAuditLog(t *testing.T) {
+	srv, audit := authSetup(t, config.AdminConfig{Keys: testAdminKeys})
+	adminRequest(srv, http.MethodPost, "/admin/replay?status=failed", "Authorization", "Bearer op-key")
+	adminRequest(srv, http.MethodGet, "/admin/events", "Authorization", "Bearer nope")
+	adminRequest(srv, http.MethodPost, "/webhooks/dummy")
+
+	var records []map[string]any
+	dec := json.NewDecoder(audit)
+	for dec.More() {
+		var rec map[string]any
+		if err := dec.Decode(&rec); err != nil {
+			t.Fatal(err)
+		}
+		records = append(records, rec)
+	}
+	if len(records) != 2 {
+		t.Fatalf("got %d audit records, want 2 (admin requests only): %v", len(records), records)
+	}
+	replay := records[0]
+	if replay["caller"] != "oncall" || replay["role"] != "operator" || replay["method"] != "POST" ||
+		replay["path"] != "/admin/replay" || replay["query"] != "status=failed" || replay["status"] != float64(200) {
+		t.Errorf("replay audit record = %v", replay)
+	}
+	denied := records[1]
+	if denied["caller"] != "" || denied["status"] != float64(401) {
+		t.Errorf("denied audit record = %v", denied)
+	}
+	if strings.Contains(audit.String(), "op-key") {
+		t.Error("audit log contains an admin key")
+	}
+}
+
+func TestAdminListenerSeparate(t *testing.T) {
+	srv, _ := authSetup(t, config.AdminConfig{Port: 9091})
+
+	if rec := adminRequest(srv, http.MethodGet, "/admin/events"); rec.Code != http.StatusNotFound {
+		t.Errorf("main listener /admin/events: expected 404, got %d", rec.Code)
+	}
+	admin := srv.AdminHandler()
+	if admin == nil {
+		t.Fatal("AdminHandler() = nil with admin.port set")
+	}
+	if rec := adminRequest(admin, http.MethodGet, "/admin/events"); rec.Code != http.StatusOK {
+		t.Errorf("admin listener /admin/events: expected 200, got %d", rec.Code)
+	}
+	if rec := adminRequest(admin, http.MethodPost, "/webhooks/dummy"); rec.Code != http.StatusNotFound {
+		t.Errorf("admin listener webhook: expected 404, got %d", rec.Code)
+	}
+
+	shared, _ := authSetup(t, config.AdminConfig{})
+	if shared.AdminHandler() != nil {
+		t.Error("AdminHandler() != nil without admin.port")
+	}
+}
+
+// --- Header filtering ---
+
+// headerTestChannel is a dummyTestChannel that copies every request header
+// into the event, like the built-in channels do.
+type headerTestChannel struct{ dummyTestChannel }
+
+func (c *headerTestChannel) ParseRequest(r *http.Request) (*types.Event, error) {
+	evt, err := c.dummyTestChannel.ParseRequest(r)
+	if err != nil {
+		return nil, err
+	}
+	evt.ID = uuid.New()
+	for name := range r.Header {
+		evt.Headers[name] = r.Header.Get(name)
+	}
+	return evt, nil
+}
+
+func TestWebhookFiltersHeaders(t *testing.T) {
+	cfg := &config.Config{
+		Server: config.ServerConfig{Host: "127.0.0.1"},
+		Channels: []config.ChannelConfig{{
+			Name: "filtered", Type: "dummy",
+			Headers: config.HeaderConfig{Deny: []string{"User-Agent"}, Redact: []string{"X-Customer"}},
+		}, {
+			// An hmac channel's signature header is redacted whatever its name.
+			Name: "signed", Type: "hmac", HMAC: config.HMACConfig{Header: "X-Customer"},
+		}},
+	}
+	channels := map[string]types.Channel{
+		"filtered":   &headerTestChannel{dummyTestChannel{name: "filtered"}},
+		"unfiltered": &headerTestChannel{dummyTestChannel{name: "unfiltered"}},
+		"signed":     &headerTestChannel{dummyTestChannel{name: "signed"}},
+	}
+	recorder := &recordingAgent{}
+	srv := NewServer(cfg, mustMemoryStore(t), channels, recorder, nil, slog.Default())
+
+	for _, channel := range []string{"filtered", "unfiltered", "signed"} {
+		req := httptest.NewRequest(http.MethodPost, "/webhooks/"+channel, strings.NewReader(`{}`))
+		req.Header.Set("Authorization", "Bearer secret")
+		req.Header.Set("User-Agent", "curl")
+		req.Header.Set("X-Customer", "acme")
+		srv.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	if len(recorder.envelopes) != 3 {
+		t.Fatalf("agent received %d envelopes, want 3", len(recorder.envelopes))
+	}
+
+	want := map[string]map[string]string{
+		"filtered":   {"Authorization": redact.Redacted, "X-Customer": redact.Redacted},
+		"unfiltered": {"Authorization": redact.Redacted, "User-Agent": "curl", "X-Customer": "acme"},
+		"signed":     {"Authorization": redact.Redacted, "User-Agent": "curl", "X-Customer": redact.Redacted},
+	}
+	for _, env := range recorder.envelopes {
+		got := env.Event.Headers
+		delete(got, "Content-Type")
+		if !maps.Equal(got, want[env.Channel]) {
+			t.Errorf("%s forwarded headers = %v, want %v", env.Channel, got, want[env.Channel])
+		}
+		stored, err := srv.store.Get(env.Event.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stored.Headers["Authorization"] != redact.Redacted {
+			t.Errorf("%s stored Authorization = %q, want redacted", env.Channel, stored.Headers["Authorization"])
+		}
+	}
+}
+
+// --- Body redaction ---
+
+func TestWebhookRedactsBody(t *testing.T) {
+	cfg := &config.Config{
+		Server: config.ServerConfig{Host: "127.0.0.1"},
+		Channels: []config.ChannelConfig{{
+			Name: "dummy", Type: "dummy",
+			Redact: config.RedactConfig{
+				Store:   config.RedactRules{Paths: []string{"token"}},
+				Forward: config.RedactRules{Patterns: []string{`[a-z]+@example\.com`}},
+			},
+		}},
+	}
+	channels := map[string]types.Channel{"dummy": &dummyTestChannel{name: "dummy"}}
+	recorder := &recordingAgent{}
+	srv := NewServer(cfg, mustMemoryStore(t), channels, recorder, nil, slog.Default())
+
+	body := `{"token":"s3cret","owner":"ops@example.com"}`
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/dummy", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	env := recorder.envelopes[0]
+	stored, err := srv.store.Get(env.Event.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"owner":"ops@example.com","token":"[REDACTED]"}`; string(stored.RawBody) != want {
+		t.Errorf("stored body = %s, want %s", stored.RawBody, want)
+	}
+	if stored.Metadata[redact.MetadataKey] != "1" {
+		t.Errorf("stored metadata = %v, want 1 redaction", stored.Metadata)
+	}
+	if want := `{"owner":"[REDACTED]","token":"[REDACTED]"}`; string(env.Event.RawBody) != want {
+		t.Errorf("forwarded body = %s, want %s", env.Event.RawBody, want)
+	}
+	if env.Event.Metadata[redact.MetadataKey] != "2" {
+		t.Errorf("forwarded metadata = %v, want 2 redactions", env.Event.Metadata)
+	}
+}
+
+// --- Channel replies ---
+
+// replyingTestChannel answers every request itself.
+type replyingTestChannel struct{ dummyTestChannel }
+
+func (c *replyingTestChannel) ParseRequest(*http.Request) (*types.Event, error) {
+	return nil, &types.Reply{Status: http.StatusOK, Body: map[string]string{"challenge": "abc"}}
+}
+
+func TestWebhookChannelReply(t *testing.T) {
+	cfg := &config.Config{Server: config.ServerConfig{Host: "127.0.0.1"}}
+	channels := map[string]types.Channel{"handshake": &replyingTestChannel{dummyTestChannel{name: "handshake"}}}
+	recorder := &recordingAgent{}
+	srv := NewServer(cfg, mustMemoryStore(t), channels, recorder, nil, slog.Default())
+
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/handshake", strings.NewReader(`{}`))
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	var body map[string]string
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if body["challenge"] != "abc" {
+		t.Errorf("body = %v, want the channel's reply", body)
+	}
+	if srv.store.Count() != 0 || len(recorder.envelopes) != 0 {
+		t.Errorf("stored %d events and forwarded %d, want none", srv.store.Count(), len(recorder.envelopes))
+	}
+	if stats := getStats(t, srv); stats.Channels["handshake"].ParseFailures != 0 {
+		t.Error("a reply was counted as a parse failure")
+	}
+}
+
+// --- Batches ---
+
+// batchTestChannel treats a request with X-Batch set as a JSON array of
+// event bodies.
+type batchTestChannel struct{ dummyTestChannel }
+
+func (c *batchTestChannel) IsBatch(r *http.Request) bool { return r.Header.Get("X-Batch") != "" }
+
+func (c *batchTestChannel) ParseBatch(r *http.Request) ([]*types.Event, error) {
+	var bodies []json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&bodies); err != nil {
+		return nil, err
+	}
+	events := make([]*types.Event, 0, len(bodies))
+	for _, body := range bodies {
+		events = append(events, &types.Event{
+			ID:        uuid.New(),
+			ChannelID: c.name,
+			RawBody:   body,
+			Headers:   map[string]string{"Authorization": "Bearer secret"},
+			Status:    types.EventStatusReceived,
+		})
+	}
+	return events, nil
+}
+
+func batchSetup(t *testing.T, agentClient agent.Client, mode string) *Server {
+	t.Helper()
+	cfg := &config.Config{
+		Server:   config.ServerConfig{Host: "127.0.0.1", Workers: 1, QueueSize: 10},
+		Channels: []config.ChannelConfig{{Name: "batch", Type: "dummy", Mode: mode}},
+	}
+	channels := map[string]types.Channel{"batch": &batchTestChannel{dummyTestChannel{name: "batch"}}}
+	return NewServer(cfg, mustMemoryStore(t), channels, agentClient, nil, slog.Default())
+}
+
+func postBatch(srv *Server, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/batch", strings.NewReader(body))
+	req.Header.Set("X-Batch", "1")
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestWebhookBatchSync(t *testing.T) {
+	recorder := &recordingAgent{}
+	srv := batchSetup(t, recorder, "sync")
+
+	rec := postBatch(srv, `[{"n":1},{"n":2},{"n":3}]`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var body struct {
+		Results []batchResult `json:"results"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if len(body.Results) != 3 {
+		t.Fatalf("got %d results, want 3", len(body.Results))
+	}
+	for i, res := range body.Results {
+		if res.Response == nil || res.Response.EventID != res.EventID || res.Error != "" {
+			t.Errorf("results[%d] = %+v, want the agent's response", i, res)
+		}
+	}
+
+	if len(recorder.envelopes) != 3 {
+		t.Fatalf("agent received %d envelopes, want 3", len(recorder.envelopes))
+	}
+	for i, env := range recorder.envelopes {
+		if want := fmt.Sprintf(`{"n":%d}`, i+1); string(env.Event.RawBody) != want {
+			t.Errorf("envelope %d body = %s, want %s", i, env.Event.RawBody, want)
+		}
+		if env.Event.Headers["Authorization"] != redact.Redacted {
+			t.Errorf("envelope %d headers were not filtered", i)
+		}
+		stored, err := srv.store.Get(env.Event.ID)
+		if err != nil || stored.Status != types.EventStatusForwarded {
+			t.Errorf("event %d stored as %+v (%v), want forwarded", i, stored, err)
+		}
+	}
+}
+
+func TestWebhookBatchSyncForwardFailure(t *testing.T) {
+	srv := batchSetup(t, &failingAgent{err: errors.New("agent down")}, "sync")
+
+	rec := postBatch(srv, `[{"n":1},{"n":2}]`)
+	if rec.Code != http.StatusBadGateway {
+		t.Fatalf("expected 502, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var body struct {
+		Results []batchResult `json:"results"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if len(body.Results) != 2 || body.Results[0].Error == "" || body.Results[1].Error == "" {
+		t.Errorf("results = %+v, want an error for each event", body.Results)
+	}
+
+	// With the breaker open every forward is refused, which is a 503 as for
+	// a single webhook.
+	srv = batchSetup(t, &failingAgent{err: agent.ErrCircuitOpen}, "sync")
+	if rec := postBatch(srv, `[{"n":1},{"n":2}]`); rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("breaker open: expected 503, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestWebhookBatchAsync(t *testing.T) {
+	srv := batchSetup(t, &agent.StubClient{Logger: slog.Default()}, "async")
+
+	rec := postBatch(srv, `[{"n":1},{"n":2}]`)
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var body struct {
+		Status   string   `json:"status"`
+		EventIDs []string `json:"event_ids"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Status != "accepted" || len(body.EventIDs) != 2 {
+		t.Fatalf("body = %+v, want both events accepted", body)
+	}
+
+	if err := srv.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+	for _, id := range body.EventIDs {
+		evt, err := srv.store.Get(uuid.MustParse(id))
+		if err != nil || evt.Status != types.EventStatusForwarded {
+			t.Errorf("event %s = %+v (%v), want forwarded", id, evt, err)
+		}
+	}
+}
+
+func TestWebhookBatchParseError(t *testing.T) {
+	srv := batchSetup(t, &recordingAgent{}, "sync")
+
+	if rec := postBatch(srv, `{"not":"an array"}`); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", rec.Code)
+	}
+	if srv.store.Count() != 0 {
+		t.Errorf("stored %d events from a rejected batch", srv.store.Count())
+	}
+}
+
+// --- Deduplicating channels ---
+
+func TestWebhookFailureLetsSlackRetryThrough(t *testing.T) {
+	cfg := &config.Config{
+		Server:   config.ServerConfig{Host: "127.0.0.1"},
+		Channels: []config.ChannelConfig{{Name: "slack", Type: "slack", Secret: "s3cret"}},
+	}
+	switchable := &switchableAgent{failing: true}
+	channels := map[string]types.Channel{"slack": channel.NewSlackChannel("slack", "s3cret")}
+	srv := NewServer(cfg, mustMemoryStore(t), channels, switchable, nil, slog.Default())
+
+	body := `{"type":"event_callback","event_id":"Ev1","event":{"type":"app_mention"}}`
+	post := func(retry string) *httptest.ResponseRecorder {
+		ts := strconv.FormatInt(time.Now().Unix(), 10)
+		mac := hmac.New(sha256.New, []byte("s3cret"))
+		mac.Write([]byte("v0:" + ts + ":" + body))
+		req := httptest.NewRequest(http.MethodPost, "/webhooks/slack", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Slack-Request-Timestamp", ts)
+		req.Header.Set("X-Slack-Signature", "v0="+hex.EncodeToString(mac.Sum(nil)))
+		if retry != "" {
+			req.Header.Set("X-Slack-Retry-Num", retry)
+		}
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := post(""); rec.Code != http.StatusBadGateway {
+		t.Fatalf("first delivery: expected 502, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	switchable.set(false)
+	rec := post("1")
+	if rec.Code != http.StatusOK || strings.Contains(rec.Body.String(), "duplicate") {
+		t.Fatalf("retry: expected the event forwarded, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	// Now that it has been processed, a further retry is dropped.
+	rec = post("2")
+	if !strings.Contains(rec.Body.String(), "duplicate") {
+		t.Fatalf("second retry: expected a duplicate reply, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if n := srv.store.Count(); n != 2 {
+		t.Errorf("stored %d events, want the failed delivery and the retry", n)
+	}
+}
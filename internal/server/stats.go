@@ -0,0 +1,224 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/youmna-rabie/claude-pod/internal/agent"
+	"github.com/youmna-rabie/claude-pod/internal/types"
+)
+
+// latencyWindow is how many recent forward latencies the percentiles cover.
+const latencyWindow = 1024
+
+// stats tracks per-channel health and agent performance since the gateway
+// started. It is safe for concurrent use.
+type stats struct {
+	mu       sync.Mutex
+	started  time.Time
+	channels map[string]*channelStats
+
+	forwards   uint64
+	succeeded  uint64
+	rejections uint64          // forwards refused by the open circuit breaker
+	latencies  []time.Duration // ring of the most recent forward latencies
+	next       int             // next write position in latencies
+}
+
+// channelStats is the health of one channel.
+type channelStats struct {
+	Statuses           map[types.EventStatus]uint64 `json:"statuses"`
+	ValidationFailures uint64                       `json:"validation_failures"`
+	ParseFailures      uint64                       `json:"parse_failures"`
+	RateLimited        uint64                       `json:"rate_limited"`
+	LastEventAt        *time.Time                   `json:"last_event_at"`
+	LastError          string                       `json:"last_error,omitempty"`
+	LastErrorAt        *time.Time                   `json:"last_error_at,omitempty"`
+}
+
+// newStats creates a tracker listing every configured channel, so a channel
+// that never receives anything still shows up with a null last_event_at.
+func newStats(channels []string) *stats {
+	st := &stats{
+		started:  time.Now(),
+		channels: make(map[string]*channelStats, len(channels)),
+	}
+	for _, name := range channels {
+		st.channel(name)
+	}
+	return st
+}
+
+// channel returns the stats for name, creating them if needed. Callers must hold st.mu.
+func (st *stats) channel(name string) *channelStats {
+	cs, ok := st.channels[name]
+	if !ok {
+		cs = &channelStats{Statuses: make(map[types.EventStatus]uint64)}
+		st.channels[name] = cs
+	}
+	return cs
+}
+
+// setError records err as the channel's most recent error. Callers must hold st.mu.
+func (cs *channelStats) setError(msg string) {
+	now := time.Now()
+	cs.LastError = msg
+	cs.LastErrorAt = &now
+}
+
+// received records a stored webhook event.
+func (st *stats) received(channel string, at time.Time) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	cs := st.channel(channel)
+	cs.Statuses[types.EventStatusReceived]++
+	if cs.LastEventAt == nil || at.After(*cs.LastEventAt) {
+		cs.LastEventAt = &at
+	}
+}
+
+// rejected records a webhook that failed validation or parsing.
+func (st *stats) rejected(channel, stage string, err error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	cs := st.channel(channel)
+	switch stage {
+	case "validation":
+		cs.ValidationFailures++
+	case "parse":
+		cs.ParseFailures++
+	}
+	cs.setError(stage + ": " + err.Error())
+}
+
+// rateLimited records a webhook rejected by the channel's rate limit. It
+// leaves last_error alone so a flood does not hide the last real failure.
+func (st *stats) rateLimited(channel string) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.channel(channel).RateLimited++
+}
+
+// forwarded records the outcome of an agent forward that ended in status.
+// Forwards refused by the open circuit breaker never reached the agent, so
+// they are counted apart and left out of its success rate and latency.
+func (st *stats) forwarded(channel string, d time.Duration, status types.EventStatus, err error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	cs := st.channel(channel)
+	cs.Statuses[status]++
+	if err != nil {
+		cs.setError(err.Error())
+	}
+	if errors.Is(err, agent.ErrCircuitOpen) {
+		st.rejections++
+		return
+	}
+
+	st.forwards++
+	if err == nil {
+		st.succeeded++
+	}
+	if len(st.latencies) < latencyWindow {
+		st.latencies = append(st.latencies, d)
+	} else {
+		st.latencies[st.next] = d
+	}
+	st.next = (st.next + 1) % latencyWindow
+}
+
+// failed records an event marked failed without a forward, for example
+// because the async queue was full.
+func (st *stats) failed(channel, reason string) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	cs := st.channel(channel)
+	cs.Statuses[types.EventStatusFailed]++
+	cs.setError(reason)
+}
+
+// statsSnapshot is the GET /admin/stats response.
+type statsSnapshot struct {
+	StartedAt     time.Time                    `json:"started_at"`
+	UptimeSeconds float64                      `json:"uptime_seconds"`
+	Events        map[string]int               `json:"events"`
+	Totals        map[types.EventStatus]uint64 `json:"totals"`
+	Channels      map[string]channelStats      `json:"channels"`
+	Agent         agentStats                   `json:"agent"`
+}
+
+// agentStats summarises forwards to the agent.
+type agentStats struct {
+	Forwards    uint64  `json:"forwards"`
+	Succeeded   uint64  `json:"succeeded"`
+	Failed      uint64  `json:"failed"`
+	SuccessRate float64 `json:"success_rate"` // 0 when nothing has been forwarded
+	// CircuitRejections counts forwards refused by the open circuit breaker,
+	// which are not included in the figures above.
+	CircuitRejections uint64 `json:"circuit_rejections"`
+	LatencyMS         struct {
+		P50     float64 `json:"p50"`
+		P95     float64 `json:"p95"`
+		Samples int     `json:"samples"`
+	} `json:"latency_ms"` // over the most recent forwards
+}
+
+// snapshot copies the current stats.
+func (st *stats) snapshot() statsSnapshot {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	snap := statsSnapshot{
+		StartedAt:     st.started,
+		UptimeSeconds: time.Since(st.started).Seconds(),
+		Totals:        make(map[types.EventStatus]uint64),
+		Channels:      make(map[string]channelStats, len(st.channels)),
+	}
+	for name, cs := range st.channels {
+		c := *cs
+		c.Statuses = make(map[types.EventStatus]uint64, len(cs.Statuses))
+		for status, n := range cs.Statuses {
+			c.Statuses[status] = n
+			snap.Totals[status] += n
+		}
+		snap.Channels[name] = c
+	}
+
+	a := &snap.Agent
+	a.Forwards, a.Succeeded, a.Failed = st.forwards, st.succeeded, st.forwards-st.succeeded
+	a.CircuitRejections = st.rejections
+	if st.forwards > 0 {
+		a.SuccessRate = float64(st.succeeded) / float64(st.forwards)
+	}
+	sorted := slices.Clone(st.latencies)
+	slices.Sort(sorted)
+	a.LatencyMS.Samples = len(sorted)
+	a.LatencyMS.P50 = percentile(sorted, 50)
+	a.LatencyMS.P95 = percentile(sorted, 95)
+	return snap
+}
+
+// percentile returns the p-th percentile of sorted, in milliseconds, by the
+// nearest-rank method. An empty slice yields 0.
+func percentile(sorted []time.Duration, p int) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := (p*len(sorted) + 99) / 100 // ceil(p/100 * n)
+	return float64(sorted[max(rank, 1)-1]) / float64(time.Millisecond)
+}
+
+// handleAdminStats responds to GET /admin/stats with per-channel health,
+// agent success rate and latency, and uptime. Counts cover the time since
+// the gateway started; events counts what the stores hold now.
+func (s *Server) handleAdminStats(w http.ResponseWriter, _ *http.Request) {
+	snap := s.stats.snapshot()
+	snap.Events = map[string]int{"stored": s.store.Count()}
+	if s.dead != nil {
+		snap.Events["dead_letters"] = s.dead.Count()
+	}
+	writeJSON(w, http.StatusOK, snap)
+}
@@ -0,0 +1,196 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/youmna-rabie/claude-pod/internal/event"
+	"github.com/youmna-rabie/claude-pod/internal/types"
+)
+
+const (
+	// streamBuffer is how many notifications a stream may fall behind before
+	// it is dropped. The client then reconnects and resumes from the store.
+	streamBuffer = 256
+
+	// streamRetry is the reconnect delay suggested to clients, in milliseconds.
+	streamRetry = 2000
+)
+
+// streamHeartbeat is how often an idle stream sends a comment so proxies
+// keep the connection open. Tests shorten it.
+var streamHeartbeat = 15 * time.Second
+
+// CloseStreams ends every open event stream. It is meant for
+// http.Server.RegisterOnShutdown, since a stream never finishes on its own
+// and would otherwise hold up a graceful shutdown.
+func (s *Server) CloseStreams() {
+	s.streamsOnce.Do(func() { close(s.streamsDone) })
+}
+
+// handleEventStream responds to GET /admin/events/stream with Server-Sent
+// Events: an "event" message for each new event and a "status" message for
+// each status change, optionally filtered by channel and status. Only
+// "event" messages carry an id, so a reconnecting client's Last-Event-ID
+// names the newest event it saw; events saved after it are sent from the
+// store, with their current status, before the live stream resumes.
+func (s *Server) handleEventStream(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	filter := streamFilter{
+		channel: params.Get("channel"),
+		status:  types.EventStatus(params.Get("status")),
+	}
+
+	var lastID uuid.UUID
+	if v := r.Header.Get("Last-Event-ID"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid Last-Event-ID"})
+			return
+		}
+		lastID = id
+	}
+
+	// Subscribe before reading the backlog so nothing saved in between is missed.
+	sub := s.events.Subscribe(streamBuffer)
+	defer sub.Close()
+
+	rc := http.NewResponseController(w)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, "retry: %d\n\n", streamRetry)
+
+	sent := make(map[uuid.UUID]bool)
+	if lastID != uuid.Nil {
+		backlog, err := s.streamBacklog(lastID, filter)
+		if err != nil {
+			s.logger.Warn("event stream resume failed", "error", err, "last_event_id", lastID)
+		}
+		for _, evt := range backlog {
+			sent[evt.ID] = true
+			if err := writeStreamMessage(w, event.Notification{Type: event.NotifySaved, Event: evt}); err != nil {
+				return
+			}
+		}
+	}
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-s.streamsDone:
+			return
+		case <-heartbeat.C:
+			if _, err := io.WriteString(w, ": ping\n\n"); err != nil {
+				return
+			}
+		case n, ok := <-sub.C:
+			if !ok {
+				s.logger.Warn("event stream fell behind, closing", "request_id", RequestIDFromContext(r.Context()))
+				return
+			}
+			if n.Type == event.NotifySaved && sent[n.Event.ID] {
+				continue
+			}
+			if !filter.match(n) {
+				continue
+			}
+			if err := writeStreamMessage(w, n); err != nil {
+				return
+			}
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// streamBacklog returns the events matching filter that were saved after
+// lastID, oldest first. An unknown lastID, for example one already evicted,
+// yields nothing.
+func (s *Server) streamBacklog(lastID uuid.UUID, filter streamFilter) ([]types.Event, error) {
+	last, err := s.store.Get(lastID)
+	if errors.Is(err, event.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	q := event.Query{
+		ChannelID: filter.channel,
+		Status:    filter.status,
+		Since:     last.Timestamp,
+		Limit:     event.MaxQueryLimit,
+	}
+	var newer []types.Event
+	for {
+		page, err := s.store.Query(q)
+		if err != nil {
+			return nil, err
+		}
+		for _, evt := range page.Events {
+			if evt.ID != lastID {
+				newer = append(newer, evt)
+			}
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		q.Cursor = page.NextCursor
+	}
+
+	slices.Reverse(newer)
+	return newer, nil
+}
+
+// streamFilter selects the notifications a stream forwards.
+type streamFilter struct {
+	channel string
+	status  types.EventStatus
+}
+
+// match reports whether n passes the filter. A status change matches on
+// the status it changed to.
+func (f streamFilter) match(n event.Notification) bool {
+	if f.channel != "" && n.Event.ChannelID != f.channel {
+		return false
+	}
+	if f.status == "" {
+		return true
+	}
+	if n.Change != nil {
+		return n.Change.Status == f.status
+	}
+	return n.Event.Status == f.status
+}
+
+// writeStreamMessage writes n as one SSE message.
+func writeStreamMessage(w io.Writer, n event.Notification) error {
+	data, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+	if n.Type == event.NotifySaved {
+		if _, err := fmt.Fprintf(w, "id: %s\n", n.Event.ID); err != nil {
+			return err
+		}
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", n.Type, data)
+	return err
+}
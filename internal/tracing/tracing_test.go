@@ -0,0 +1,95 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/youmna-rabie/claude-pod/internal/config"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const testTraceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+// restoreProvider puts back the global tracer provider when the test ends.
+func restoreProvider(t *testing.T) {
+	t.Helper()
+	old := otel.GetTracerProvider()
+	t.Cleanup(func() { otel.SetTracerProvider(old) })
+}
+
+func TestSetupFileExporter(t *testing.T) {
+	restoreProvider(t)
+	path := filepath.Join(t.TempDir(), "spans.jsonl")
+	shutdown, err := Setup(context.Background(), config.TracingConfig{
+		Exporter:    "file",
+		Path:        path,
+		ServiceName: "test-gateway",
+	})
+	if err != nil {
+		t.Fatalf("Setup: %v", err)
+	}
+
+	_, span := Tracer().Start(context.Background(), "test-span")
+	span.End()
+	if err := shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"Name":"test-span"`) || !strings.Contains(string(data), "test-gateway") {
+		t.Errorf("trace file does not contain the span and service name:\n%s", data)
+	}
+}
+
+func TestSetupNone(t *testing.T) {
+	restoreProvider(t)
+	shutdown, err := Setup(context.Background(), config.TracingConfig{Exporter: "none"})
+	if err != nil {
+		t.Fatalf("Setup: %v", err)
+	}
+	if err := shutdown(context.Background()); err != nil {
+		t.Errorf("shutdown: %v", err)
+	}
+}
+
+func TestSetupFileExporterBadPath(t *testing.T) {
+	restoreProvider(t)
+	_, err := Setup(context.Background(), config.TracingConfig{
+		Exporter: "file",
+		Path:     filepath.Join(t.TempDir(), "missing", "spans.jsonl"),
+	})
+	if err == nil {
+		t.Error("expected an error for an unwritable trace file")
+	}
+}
+
+func TestPropagation(t *testing.T) {
+	in := http.Header{}
+	in.Set("traceparent", testTraceParent)
+	ctx := Extract(context.Background(), in)
+
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() || !sc.IsRemote() || sc.TraceID().String() != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Fatalf("extracted span context = %+v", sc)
+	}
+
+	out := http.Header{}
+	Inject(ctx, out)
+	if got := out.Get("traceparent"); got != testTraceParent {
+		t.Errorf("injected traceparent = %q, want %q", got, testTraceParent)
+	}
+	if tp, _ := TraceParent(ctx); tp != testTraceParent {
+		t.Errorf("TraceParent = %q, want %q", tp, testTraceParent)
+	}
+	if tp, ts := TraceParent(context.Background()); tp != "" || ts != "" {
+		t.Errorf("TraceParent without a span = %q, %q", tp, ts)
+	}
+}
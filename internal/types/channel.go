@@ -1,6 +1,9 @@
 package types
 
-import "net/http"
+import (
+	"fmt"
+	"net/http"
+)
 
 // Channel defines the interface for an incoming webhook channel.
 type Channel interface {
@@ -8,3 +11,34 @@ type Channel interface {
 	ValidateRequest(r *http.Request) error
 	ParseRequest(r *http.Request) (*Event, error)
 }
+
+// BatchChannel is a Channel whose requests may carry several events. For a
+// request IsBatch reports true for, the gateway calls ParseBatch in place of
+// ParseRequest; other requests are parsed as usual.
+type BatchChannel interface {
+	Channel
+	IsBatch(r *http.Request) bool
+	ParseBatch(r *http.Request) ([]*Event, error)
+}
+
+// DedupChannel is implemented by channels that drop repeat deliveries of an
+// event they have already parsed. If the gateway then fails to store or
+// forward the event, it calls Forget so the sender's retry is processed
+// rather than dropped.
+type DedupChannel interface {
+	Channel
+	Forget(evt *Event)
+}
+
+// Reply is returned as the error from ParseRequest or ParseBatch for a
+// request the channel answers itself, such as a handshake or an event type
+// it ignores. The gateway responds with Status and Body as JSON and stores
+// nothing.
+type Reply struct {
+	Status int
+	Body   any
+}
+
+func (r *Reply) Error() string {
+	return fmt.Sprintf("answered by channel with status %d", r.Status)
+}
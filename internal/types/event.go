@@ -15,16 +15,31 @@ const (
 	EventStatusForwarded EventStatus = "forwarded"
 	EventStatusFailed    EventStatus = "failed"
 	EventStatusCompleted EventStatus = "completed"
+	// EventStatusDeadLettered marks an event that failed every forward
+	// attempt and was moved to the dead-letter queue.
+	EventStatusDeadLettered EventStatus = "dead_lettered"
 )
 
 // Event represents an incoming request from a channel.
 type Event struct {
-	ID        uuid.UUID          `json:"id"`
-	ChannelID string             `json:"channel_id"`
-	RawBody   json.RawMessage    `json:"raw_body"`
-	Headers   map[string]string  `json:"headers"`
-	Timestamp time.Time          `json:"timestamp"`
-	Status    EventStatus        `json:"status"`
+	ID        uuid.UUID         `json:"id"`
+	ChannelID string            `json:"channel_id"`
+	RawBody   json.RawMessage   `json:"raw_body"`
+	Headers   map[string]string `json:"headers"`
+	Timestamp time.Time         `json:"timestamp"`
+	Status    EventStatus       `json:"status"`
+	Attempts  int               `json:"attempts,omitempty"`
+	Metadata  map[string]string `json:"metadata,omitempty"` // facts the gateway or channel derived, such as a redaction count
+}
+
+// StatusChange is one entry in an event's status history.
+type StatusChange struct {
+	Status    EventStatus     `json:"status"`
+	Timestamp time.Time       `json:"timestamp"`
+	Error     string          `json:"error,omitempty"`
+	Attempt   int             `json:"attempt,omitempty"`  // forward attempts made, when the change follows a forward
+	Response  json.RawMessage `json:"response,omitempty"` // agent response body, when one was received
+	Replay    bool            `json:"replay,omitempty"`   // the change follows a replay from the admin API
 }
 
 // EventEnvelope wraps an event with routing metadata.
@@ -34,4 +49,11 @@ type EventEnvelope struct {
 	Channel   string    `json:"channel"`
 	Skills    []Skill   `json:"skills"`
 	Timestamp time.Time `json:"timestamp"`
+	RequestID string    `json:"request_id,omitempty"`
+	Replay    bool      `json:"replay,omitempty"` // the event was delivered before and is being re-sent
+
+	// TraceParent and TraceState carry the W3C trace context of the forward,
+	// for agents that read the envelope rather than the request headers.
+	TraceParent string `json:"traceparent,omitempty"`
+	TraceState  string `json:"tracestate,omitempty"`
 }